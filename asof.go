@@ -0,0 +1,119 @@
+package ppp
+
+import (
+	"context"
+	"time"
+)
+
+// historicalTTL is how long point-in-time data is cached by default
+// Past vintages don't change, so they can be kept much longer than live data
+const historicalTTL = 30 * 24 * time.Hour
+
+// asOfKey is the context key for the as-of date
+type asOfKey struct{}
+
+// ContextWithAsOf returns a context that makes client lookups point-in-time
+// PPP data is limited to the years released by date, at their current
+// revision, and exchange rates are taken from that day's snapshot
+// Example: ctx = ppp.ContextWithAsOf(ctx, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC))
+func ContextWithAsOf(ctx context.Context, date time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey{}, date)
+}
+
+// AsOfFromContext returns the as-of date stored in ctx, if any
+func AsOfFromContext(ctx context.Context) (time.Time, bool) {
+	date, ok := ctx.Value(asOfKey{}).(time.Time)
+	return date, ok
+}
+
+// RecommendAsOf calculates the price that would have been recommended on date
+func (c *Client) RecommendAsOf(ctx context.Context, date time.Time, price float64, fromCurrency, toCountry string) (*PriceRecommendation, error) {
+	return c.Recommend(ContextWithAsOf(ctx, date), price, fromCurrency, toCountry)
+}
+
+// validateAsOf rejects as-of dates in the future
+func (c *Client) validateAsOf(date time.Time) error {
+	if date.After(c.now()) {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			"as-of date is in the future",
+			ErrInvalidDateRange,
+		).WithContext("as_of", date.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// getPPPAsOf fetches the PPP data for the latest year released as of date
+// The World Bank API only serves the current revision of each year, so the
+// value is today's estimate for that year, not the figure published on date
+func (c *Client) getPPPAsOf(ctx context.Context, countryCode string, date time.Time) (*PPPData, error) {
+	if err := c.validateAsOf(date); err != nil {
+		return nil, err
+	}
+	
+	year := c.pppRelease.LatestYear(date)
+	
+	// Check cache first if enabled
+	if c.cacheEnabled && c.cache != nil {
+		ppp, found := c.cache.GetPPPForYear(countryCode, year)
+		c.observeCache(ctx, CacheFamilyPPP, CacheKeyPPPForYear(countryCode, year), found)
+		if found {
+			return ppp, nil
+		}
+	}
+	
+	ppp, err := c.worldBank.GetLatestPPP(ctx, countryCode, year)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Store in cache if enabled
+	if c.cacheEnabled && c.cache != nil {
+		ttl := c.historicalTTL()
+		if year >= c.pppRelease.LatestYear(c.now()) {
+			// The current vintage may still be revised
+			ttl = c.pppTTL(ppp)
+		}
+		c.cache.SetPPPForYear(countryCode, year, ppp, ttl)
+	}
+	
+	return ppp, nil
+}
+
+// getExchangeRateAsOf fetches the exchange rate published for date
+func (c *Client) getExchangeRateAsOf(ctx context.Context, from, to string, date time.Time) (*ExchangeRate, error) {
+	if err := c.validateAsOf(date); err != nil {
+		return nil, err
+	}
+	
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	
+	// Check cache first if enabled
+	if c.cacheEnabled && c.cache != nil {
+		rate, found := c.cache.GetExchangeRateOn(from, to, day)
+		c.observeCache(ctx, CacheFamilyRate, CacheKeyExchangeRateOn(from, to, day), found)
+		if found {
+			return rate, nil
+		}
+	}
+	
+	rate, err := c.currency.GetHistoricalRate(ctx, from, to, day)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Store in cache if enabled
+	if c.cacheEnabled && c.cache != nil {
+		c.cache.SetExchangeRateOn(from, to, day, rate, c.historicalTTL())
+	}
+	
+	return rate, nil
+}
+
+// historicalTTL returns how long point-in-time data should be cached
+func (c *Client) historicalTTL() time.Duration {
+	if c.cacheTTLSet {
+		return c.cacheDuration
+	}
+	return historicalTTL
+}
@@ -0,0 +1,116 @@
+package ppp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+// cached reports whether the client's cache holds key
+func cached(t *testing.T, client *ppp.Client, key string) bool {
+	t.Helper()
+	entries, err := client.CacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAsOf(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{"date":"2023-03-01","usd":{"try":18.8852}}`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	client := ppp.NewClient(
+		ppp.WithCurrencyURL(server.URL+"/currency-api@latest/v1"),
+		ppp.WithClock(ppp.FixedClock(now)),
+	)
+
+	ctx := ppp.ContextWithAsOf(context.Background(), time.Date(2023, 3, 1, 15, 30, 0, 0, time.UTC))
+	for i := 0; i < 2; i++ {
+		rate, err := client.GetExchangeRate(ctx, "USD", "TRY")
+		if err != nil {
+			t.Fatalf("GetExchangeRate failed: %v", err)
+		}
+		if rate.Rate != 18.8852 {
+			t.Errorf("Rate = %v, want 18.8852", rate.Rate)
+		}
+	}
+	if len(paths) != 1 || paths[0] != "/currency-api@2023-03-01/v1/currencies/usd.json" {
+		t.Errorf("Expected one dated request, got %v", paths)
+	}
+	if !cached(t, client, ppp.CacheKeyExchangeRateOn("USD", "TRY", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))) {
+		t.Error("Expected dated rate in cache")
+	}
+	if cached(t, client, ppp.CacheKeyExchangeRate("USD", "TRY")) {
+		t.Error("Dated rate must not be cached as the live rate")
+	}
+
+	future := ppp.ContextWithAsOf(context.Background(), now.Add(24*time.Hour))
+	if _, err := client.GetPPP(future, "TR"); !errors.Is(err, ppp.ErrInvalidDateRange) {
+		t.Errorf("Expected ErrInvalidDateRange for future date, got %v", err)
+	}
+}
+
+func TestRecommendationDates(t *testing.T) {
+	data, err := json.Marshal(&ppp.PriceRecommendation{PPPYear: 2023})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "exchange_rate_date") || strings.Contains(string(data), "as_of") {
+		t.Errorf("Expected unset dates to be omitted, got %s", data)
+	}
+
+	date := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	data, err = json.Marshal(&ppp.PriceRecommendation{ExchangeRateDate: &date})
+	if err != nil || !strings.Contains(string(data), `"exchange_rate_date":"2023-03-01T00:00:00Z"`) {
+		t.Errorf("Expected the exchange rate date, got %s (%v)", data, err)
+	}
+}
+
+func TestRecommendAsOf(t *testing.T) {
+	client := ppptest.NewClient(t)
+	date := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Brazil's 2022 PPP is released in July 2023, so 2021 is the latest on March 1st
+	rec, err := client.RecommendAsOf(context.Background(), date, 100, "USD", "BR")
+	if err != nil {
+		t.Fatalf("RecommendAsOf failed: %v", err)
+	}
+	if rec.PPPYear != 2021 || rec.PPPFactor != 2.48 {
+		t.Errorf("Expected 2021 PPP 2.48, got %d %v", rec.PPPYear, rec.PPPFactor)
+	}
+	if rec.ExchangeRate != 5.2201 || rec.ExchangeRateDate == nil || !rec.ExchangeRateDate.Equal(date) {
+		t.Errorf("Expected 2023-03-01 rate 5.2201, got %v on %v", rec.ExchangeRate, rec.ExchangeRateDate)
+	}
+	if rec.AsOf == nil || !rec.AsOf.Equal(date) {
+		t.Errorf("AsOf = %v, want %v", rec.AsOf, date)
+	}
+
+	// Live lookups are unaffected by cached point-in-time data
+	live, err := client.Recommend(context.Background(), 100, "USD", "BR")
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if live.PPPYear != 2024 || live.ExchangeRate != 5.5841 || live.AsOf != nil {
+		t.Errorf("Unexpected live recommendation %+v", live)
+	}
+}
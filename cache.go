@@ -5,21 +5,104 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
 )
 
+// Cache key families
+const (
+	CacheFamilyPPP        = "ppp"
+	CacheFamilyRate       = "rate"
+	CacheFamilyCountries  = "countries"
+	CacheFamilyIndicators = "indicators"
+)
+
 // Cache provides a simple caching layer for PPP data
 type Cache struct {
 	memory *cache.Cache
+	
+	mu                sync.Mutex
+	clock             Clock
+	defaultExpiration time.Duration
+	stats             map[string]*CacheStats
+	entries           map[string]entryTimes
+}
+
+// entryTimes records when an entry was stored and when it expires
+type entryTimes struct {
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+// CacheStats holds lookup counters for a cache key family
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Sets          uint64 `json:"sets"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+}
+
+// HitRatio returns the share of lookups served from cache
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// CacheEntry describes an item currently held in the cache
+type CacheEntry struct {
+	Key       string        `json:"key"`
+	Family    string        `json:"family"`
+	StoredAt  time.Time     `json:"stored_at"`
+	Age       time.Duration `json:"age"`
+	ExpiresAt time.Time     `json:"expires_at"` // zero if the entry never expires
 }
 
 // NewCache creates a new cache instance
 func NewCache(defaultExpiration, cleanupInterval time.Duration) *Cache {
-	return &Cache{
-		memory: cache.New(defaultExpiration, cleanupInterval),
+	c := &Cache{
+		memory:            cache.New(defaultExpiration, cleanupInterval),
+		clock:             SystemClock,
+		defaultExpiration: defaultExpiration,
+		stats:             make(map[string]*CacheStats),
+		entries:           make(map[string]entryTimes),
 	}
+	
+	// Entries still tracked when go-cache evicts them have expired;
+	// invalidated entries are untracked before they are deleted
+	c.memory.OnEvicted(func(key string, _ interface{}) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.entries[key]; ok {
+			delete(c.entries, key)
+			c.familyStats(key).Evictions++
+		}
+	})
+	
+	return c
+}
+
+// SetClock sets the clock used for entry ages and expiry
+func (c *Cache) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// CacheKeyFamily returns the family a cache key belongs to
+// Example: CacheKeyFamily("rate:USD:TRY") returns "rate"
+func CacheKeyFamily(key string) string {
+	if i := strings.Index(key, ":"); i >= 0 {
+		return key[:i]
+	}
+	return key
 }
 
 // CacheKey generates a cache key for PPP data
@@ -32,6 +115,16 @@ func CacheKeyExchangeRate(from, to string) string {
 	return fmt.Sprintf("rate:%s:%s", from, to)
 }
 
+// CacheKeyPPPForYear generates a cache key for PPP data published for a data year
+func CacheKeyPPPForYear(countryCode string, year int) string {
+	return fmt.Sprintf("ppp:%s:%d", countryCode, year)
+}
+
+// CacheKeyExchangeRateOn generates a cache key for an exchange rate on a date
+func CacheKeyExchangeRateOn(from, to string, date time.Time) string {
+	return fmt.Sprintf("rate:%s:%s:%s", from, to, date.Format("2006-01-02"))
+}
+
 // CacheKeyCountries generates a cache key for countries list
 func CacheKeyCountries() string {
 	return "countries:all"
@@ -42,10 +135,64 @@ func CacheKeyIndicators(search string) string {
 	return fmt.Sprintf("indicators:search:%s", search)
 }
 
+// familyStats returns the counters for the key's family; callers must hold c.mu
+func (c *Cache) familyStats(key string) *CacheStats {
+	family := CacheKeyFamily(key)
+	stats, ok := c.stats[family]
+	if !ok {
+		stats = &CacheStats{}
+		c.stats[family] = stats
+	}
+	return stats
+}
+
+// get looks up a key and records a hit or miss for its family
+func (c *Cache) get(key string) (interface{}, bool) {
+	data, found := c.memory.Get(key)
+	
+	c.mu.Lock()
+	// Entries can expire on the cache clock before go-cache notices
+	expired := false
+	if times, ok := c.entries[key]; found && ok && !times.expiresAt.IsZero() && !c.clock.Now().Before(times.expiresAt) {
+		found = false
+		expired = true
+	}
+	if found {
+		c.familyStats(key).Hits++
+	} else {
+		c.familyStats(key).Misses++
+	}
+	c.mu.Unlock()
+	
+	if expired {
+		c.memory.Delete(key)
+	}
+	
+	return data, found
+}
+
+// set stores a value and records when it was stored
+func (c *Cache) set(key string, value interface{}, expiration time.Duration) {
+	c.mu.Lock()
+	now := c.clock.Now()
+	times := entryTimes{storedAt: now}
+	if expiration == cache.DefaultExpiration {
+		expiration = c.defaultExpiration
+	}
+	if expiration > 0 {
+		times.expiresAt = now.Add(expiration)
+	}
+	c.entries[key] = times
+	c.familyStats(key).Sets++
+	c.mu.Unlock()
+	
+	c.memory.Set(key, value, expiration)
+}
+
 // GetPPP retrieves PPP data from cache
 func (c *Cache) GetPPP(countryCode string) (*PPPData, bool) {
 	key := CacheKeyPPP(countryCode)
-	if data, found := c.memory.Get(key); found {
+	if data, found := c.get(key); found {
 		if ppp, ok := data.(*PPPData); ok {
 			return ppp, true
 		}
@@ -56,13 +203,30 @@ func (c *Cache) GetPPP(countryCode string) (*PPPData, bool) {
 // SetPPP stores PPP data in cache
 func (c *Cache) SetPPP(countryCode string, data *PPPData, expiration time.Duration) {
 	key := CacheKeyPPP(countryCode)
-	c.memory.Set(key, data, expiration)
+	c.set(key, data, expiration)
+}
+
+// GetPPPForYear retrieves PPP data published up to a data year from cache
+func (c *Cache) GetPPPForYear(countryCode string, year int) (*PPPData, bool) {
+	key := CacheKeyPPPForYear(countryCode, year)
+	if data, found := c.get(key); found {
+		if ppp, ok := data.(*PPPData); ok {
+			return ppp, true
+		}
+	}
+	return nil, false
+}
+
+// SetPPPForYear stores PPP data published up to a data year in cache
+func (c *Cache) SetPPPForYear(countryCode string, year int, data *PPPData, expiration time.Duration) {
+	key := CacheKeyPPPForYear(countryCode, year)
+	c.set(key, data, expiration)
 }
 
 // GetExchangeRate retrieves exchange rate from cache
 func (c *Cache) GetExchangeRate(from, to string) (*ExchangeRate, bool) {
 	key := CacheKeyExchangeRate(from, to)
-	if data, found := c.memory.Get(key); found {
+	if data, found := c.get(key); found {
 		if rate, ok := data.(*ExchangeRate); ok {
 			return rate, true
 		}
@@ -73,13 +237,30 @@ func (c *Cache) GetExchangeRate(from, to string) (*ExchangeRate, bool) {
 // SetExchangeRate stores exchange rate in cache
 func (c *Cache) SetExchangeRate(from, to string, rate *ExchangeRate, expiration time.Duration) {
 	key := CacheKeyExchangeRate(from, to)
-	c.memory.Set(key, rate, expiration)
+	c.set(key, rate, expiration)
+}
+
+// GetExchangeRateOn retrieves an exchange rate for a date from cache
+func (c *Cache) GetExchangeRateOn(from, to string, date time.Time) (*ExchangeRate, bool) {
+	key := CacheKeyExchangeRateOn(from, to, date)
+	if data, found := c.get(key); found {
+		if rate, ok := data.(*ExchangeRate); ok {
+			return rate, true
+		}
+	}
+	return nil, false
+}
+
+// SetExchangeRateOn stores an exchange rate for a date in cache
+func (c *Cache) SetExchangeRateOn(from, to string, date time.Time, rate *ExchangeRate, expiration time.Duration) {
+	key := CacheKeyExchangeRateOn(from, to, date)
+	c.set(key, rate, expiration)
 }
 
 // GetCountries retrieves countries list from cache
 func (c *Cache) GetCountries() ([]Country, bool) {
 	key := CacheKeyCountries()
-	if data, found := c.memory.Get(key); found {
+	if data, found := c.get(key); found {
 		if countries, ok := data.([]Country); ok {
 			return countries, true
 		}
@@ -90,13 +271,13 @@ func (c *Cache) GetCountries() ([]Country, bool) {
 // SetCountries stores countries list in cache
 func (c *Cache) SetCountries(countries []Country, expiration time.Duration) {
 	key := CacheKeyCountries()
-	c.memory.Set(key, countries, expiration)
+	c.set(key, countries, expiration)
 }
 
 // GetIndicators retrieves indicators from cache
 func (c *Cache) GetIndicators(search string) ([]Indicator, bool) {
 	key := CacheKeyIndicators(search)
-	if data, found := c.memory.Get(key); found {
+	if data, found := c.get(key); found {
 		if indicators, ok := data.([]Indicator); ok {
 			return indicators, true
 		}
@@ -107,12 +288,115 @@ func (c *Cache) GetIndicators(search string) ([]Indicator, bool) {
 // SetIndicators stores indicators in cache
 func (c *Cache) SetIndicators(search string, indicators []Indicator, expiration time.Duration) {
 	key := CacheKeyIndicators(search)
-	c.memory.Set(key, indicators, expiration)
+	c.set(key, indicators, expiration)
 }
 
 // Clear removes all items from cache
 func (c *Cache) Clear() {
 	c.memory.Flush()
+	
+	c.mu.Lock()
+	c.entries = make(map[string]entryTimes)
+	c.mu.Unlock()
+}
+
+// Stats returns a snapshot of the lookup counters keyed by family
+func (c *Cache) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	snapshot := make(map[string]CacheStats, len(c.stats))
+	for family, stats := range c.stats {
+		snapshot[family] = *stats
+	}
+	return snapshot
+}
+
+// ResetStats zeroes all lookup counters
+func (c *Cache) ResetStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = make(map[string]*CacheStats)
+}
+
+// Entries lists the unexpired items in the cache, sorted by key
+func (c *Cache) Entries() []CacheEntry {
+	items := c.memory.Items()
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	
+	entries := make([]CacheEntry, 0, len(items))
+	for key := range items {
+		times := c.entries[key]
+		if !times.expiresAt.IsZero() && !now.Before(times.expiresAt) {
+			continue
+		}
+		entries = append(entries, CacheEntry{
+			Key:       key,
+			Family:    CacheKeyFamily(key),
+			StoredAt:  times.storedAt,
+			Age:       now.Sub(times.storedAt),
+			ExpiresAt: times.expiresAt,
+		})
+	}
+	
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	
+	return entries
+}
+
+// InvalidatePrefix removes all items whose key starts with prefix
+// Returns the number of removed items
+func (c *Cache) InvalidatePrefix(prefix string) int {
+	return c.invalidate(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// InvalidateCountry removes all PPP data cached for a country
+// Returns the number of removed items
+func (c *Cache) InvalidateCountry(countryCode string) int {
+	return c.invalidate(func(key string) bool {
+		parts := strings.Split(key, ":")
+		return parts[0] == CacheFamilyPPP && len(parts) > 1 && strings.EqualFold(parts[1], countryCode)
+	})
+}
+
+// InvalidateCurrency removes all exchange rates involving a currency
+// Returns the number of removed items
+func (c *Cache) InvalidateCurrency(currencyCode string) int {
+	return c.invalidate(func(key string) bool {
+		parts := strings.Split(key, ":")
+		if parts[0] != CacheFamilyRate || len(parts) < 3 {
+			return false
+		}
+		return strings.EqualFold(parts[1], currencyCode) || strings.EqualFold(parts[2], currencyCode)
+	})
+}
+
+// invalidate removes all items whose key matches
+func (c *Cache) invalidate(match func(key string) bool) int {
+	var keys []string
+	for key := range c.memory.Items() {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	
+	for _, key := range keys {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.familyStats(key).Invalidations++
+		c.mu.Unlock()
+		
+		c.memory.Delete(key)
+	}
+	
+	return len(keys)
 }
 
 // ExportToFile exports cache data to a JSON file
@@ -161,22 +445,22 @@ func (c *Cache) ImportFromFile(filename string) error {
 		case len(key) > 4 && key[:4] == "ppp:":
 			var ppp PPPData
 			if err := json.Unmarshal(rawData, &ppp); err == nil {
-				c.memory.Set(key, &ppp, cache.DefaultExpiration)
+				c.set(key, &ppp, cache.DefaultExpiration)
 			}
 		case len(key) > 5 && key[:5] == "rate:":
 			var rate ExchangeRate
 			if err := json.Unmarshal(rawData, &rate); err == nil {
-				c.memory.Set(key, &rate, cache.DefaultExpiration)
+				c.set(key, &rate, cache.DefaultExpiration)
 			}
 		case key == "countries:all":
 			var countries []Country
 			if err := json.Unmarshal(rawData, &countries); err == nil {
-				c.memory.Set(key, countries, cache.DefaultExpiration)
+				c.set(key, countries, cache.DefaultExpiration)
 			}
 		case len(key) > 17 && key[:17] == "indicators:search":
 			var indicators []Indicator
 			if err := json.Unmarshal(rawData, &indicators); err == nil {
-				c.memory.Set(key, indicators, cache.DefaultExpiration)
+				c.set(key, indicators, cache.DefaultExpiration)
 			}
 		}
 	}
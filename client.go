@@ -3,7 +3,10 @@ package ppp
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/go-resty/resty/v2"
 )
 
 // Client is the main PPP client
@@ -13,6 +16,17 @@ type Client struct {
 	cache         *Cache
 	cacheEnabled  bool
 	cacheDuration time.Duration
+	cacheTTLSet   bool
+	familyTTLs    map[string]time.Duration
+	pppRelease    ReleaseSchedule
+	rateLimits    map[string]rateLimit
+	breaker       *breakerConfig
+	transport     http.RoundTripper
+	observers     []Observer
+	tracer        Tracer
+	liveCountries bool
+	offline       bool
+	clock         Clock
 	timeout       time.Duration
 }
 
@@ -20,14 +34,53 @@ type Client struct {
 type Option func(*Client)
 
 // WithCache enables caching with the specified duration
+// The duration applies to every data family without its own TTL option
 func WithCache(duration time.Duration) Option {
 	return func(c *Client) {
 		c.cacheEnabled = true
 		c.cacheDuration = duration
+		c.cacheTTLSet = true
 		c.cache = NewCache(duration, duration*2)
 	}
 }
 
+// WithPPPTTL sets how long PPP data is cached
+// By default PPP data expires on the World Bank's annual release cadence
+func WithPPPTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.familyTTLs[CacheFamilyPPP] = ttl
+	}
+}
+
+// WithRateTTL sets how long exchange rates are cached (default 1 hour)
+func WithRateTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.familyTTLs[CacheFamilyRate] = ttl
+	}
+}
+
+// WithCountriesTTL sets how long the country list is cached (default 7 days)
+func WithCountriesTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.familyTTLs[CacheFamilyCountries] = ttl
+	}
+}
+
+// WithIndicatorsTTL sets how long indicator searches are cached (default 24 hours)
+func WithIndicatorsTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.familyTTLs[CacheFamilyIndicators] = ttl
+	}
+}
+
+// WithPPPReleaseSchedule sets when the World Bank publishes new PPP data
+// Used to expire PPP entries when no PPP TTL is configured
+func WithPPPReleaseSchedule(schedule ReleaseSchedule) Option {
+	return func(c *Client) {
+		c.pppRelease = schedule
+	}
+}
+
 // WithoutCache disables caching
 func WithoutCache() Option {
 	return func(c *Client) {
@@ -36,6 +89,23 @@ func WithoutCache() Option {
 	}
 }
 
+// WithLiveCountries fetches the country list from the World Bank instead of
+// using the embedded snapshot
+func WithLiveCountries() Option {
+	return func(c *Client) {
+		c.liveCountries = true
+	}
+}
+
+// WithOffline disables network access: data comes only from the cache and
+// the embedded snapshots, and anything else fails with ErrOffline
+// Useful with ImportCache for air-gapped builds and reproducible runs
+func WithOffline() Option {
+	return func(c *Client) {
+		c.offline = true
+	}
+}
+
 // WithTimeout sets the client timeout
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
@@ -57,6 +127,60 @@ func WithCurrencyURL(url string) Option {
 	}
 }
 
+// WithHTTPTransport sets the HTTP transport used for all upstream requests
+// Useful for proxies, custom TLS settings, and recording or faking responses in tests
+func WithHTTPTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithClock sets the clock used for date windows, timestamps and cache expiry
+// Example: WithClock(FixedClock(asOf)) runs the client as of a past date
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// WithRateLimit limits requests to every upstream host using a token bucket
+// refilled at requestsPerSecond and holding up to burst tokens
+// A requestsPerSecond of 0 or less means no limit; a burst below 1 counts as 1
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return WithHostRateLimit("", requestsPerSecond, burst)
+}
+
+// WithHostRateLimit limits requests to one upstream host, e.g. "api.worldbank.org"
+// Overrides WithRateLimit for that host; a requestsPerSecond of 0 or less
+// leaves the host unlimited
+func WithHostRateLimit(host string, requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimits[host] = rateLimit{perSecond: requestsPerSecond, burst: burst}
+	}
+}
+
+// WithCircuitBreaker stops calling an upstream host after failureThreshold
+// consecutive failures and lets a probe request through after cooldown
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		probes := 1
+		if c.breaker != nil {
+			probes = c.breaker.probes
+		}
+		c.breaker = &breakerConfig{threshold: failureThreshold, cooldown: cooldown, probes: probes}
+	}
+}
+
+// WithHalfOpenProbes sets how many probe requests a half-open circuit breaker allows
+func WithHalfOpenProbes(probes int) Option {
+	return func(c *Client) {
+		if c.breaker == nil {
+			c.breaker = &breakerConfig{threshold: 5, cooldown: 30 * time.Second}
+		}
+		c.breaker.probes = probes
+	}
+}
+
 // NewClient creates a new PPP client with options
 func NewClient(opts ...Option) *Client {
 	// Default client with cache enabled
@@ -65,6 +189,10 @@ func NewClient(opts ...Option) *Client {
 		currency:      NewCurrencyClient(""),
 		cacheEnabled:  true,
 		cacheDuration: 24 * time.Hour,
+		familyTTLs:    make(map[string]time.Duration),
+		pppRelease:    DefaultPPPRelease,
+		rateLimits:    make(map[string]rateLimit),
+		clock:         SystemClock,
 		timeout:       30 * time.Second,
 	}
 	
@@ -76,19 +204,99 @@ func NewClient(opts ...Option) *Client {
 		opt(client)
 	}
 	
+	client.configureTransport()
+	client.configureClock()
+	
 	return client
 }
 
+// configureClock shares the client clock with the cache and API clients
+func (c *Client) configureClock() {
+	c.worldBank.clock = c.clock
+	c.currency.clock = c.clock
+	if c.cache != nil {
+		c.cache.SetClock(c.clock)
+	}
+}
+
+// now returns the current time on the client clock
+func (c *Client) now() time.Time {
+	return c.clock.Now()
+}
+
+// configureTransport installs the custom transport, rate limiting,
+// circuit breaking and observers on the upstream HTTP clients
+func (c *Client) configureTransport() {
+	upstreams := map[string]*resty.Client{
+		UpstreamWorldBank: c.worldBank.client,
+		UpstreamCurrency:  c.currency.client,
+	}
+	
+	for _, rc := range upstreams {
+		if c.offline {
+			rc.SetTransport(offlineTransport{})
+			rc.SetRetryCount(0)
+		} else if c.transport != nil {
+			rc.SetTransport(c.transport)
+		}
+	}
+	
+	if len(c.rateLimits) > 0 || c.breaker != nil {
+		// One guard for both upstreams so limits are tracked per host, not per client
+		guard := newUpstreamGuard(c)
+		for _, rc := range upstreams {
+			rc.SetTransport(&guardedTransport{guard: guard, base: rc.GetClient().Transport})
+			rc.AddRetryCondition(func(_ *resty.Response, err error) bool {
+				return err != nil && !isGuardError(err)
+			})
+		}
+	}
+	
+	if len(c.observers) > 0 || c.tracer != nil {
+		// Outermost, so observers also see requests rejected by the guards
+		for name, rc := range upstreams {
+			rc.SetTransport(&observedTransport{
+				upstream:  name,
+				observers: c.observers,
+				tracer:    c.tracer,
+				base:      rc.GetClient().Transport,
+			})
+		}
+	}
+}
+
+// offlineTransport rejects every request made in offline mode
+type offlineTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
 // GetPPP fetches PPP data for a country
+// Honours an as-of date set with ContextWithAsOf; alpha-3, numeric and
+// lowercase codes are normalized to alpha-2
 func (c *Client) GetPPP(ctx context.Context, countryCode string) (*PPPData, error) {
+	countryCode = canonicalCountry(countryCode)
+	
+	if date, ok := AsOfFromContext(ctx); ok {
+		return c.getPPPAsOf(ctx, countryCode, date)
+	}
+	
 	// Check cache first if enabled
 	if c.cacheEnabled && c.cache != nil {
-		if ppp, found := c.cache.GetPPP(countryCode); found {
+		ppp, found := c.cache.GetPPP(countryCode)
+		c.observeCache(ctx, CacheFamilyPPP, CacheKeyPPP(countryCode), found)
+		if found {
 			return ppp, nil
 		}
 	}
 	
-	// Fetch from API
+	return c.fetchPPP(ctx, countryCode)
+}
+
+// fetchPPP fetches PPP data from the API, bypassing the cache, and stores the result
+func (c *Client) fetchPPP(ctx context.Context, countryCode string) (*PPPData, error) {
 	ppp, err := c.worldBank.GetPPP(ctx, countryCode)
 	if err != nil {
 		return nil, err
@@ -96,17 +304,24 @@ func (c *Client) GetPPP(ctx context.Context, countryCode string) (*PPPData, erro
 	
 	// Store in cache if enabled
 	if c.cacheEnabled && c.cache != nil {
-		c.cache.SetPPP(countryCode, ppp, c.cacheDuration)
+		c.cache.SetPPP(countryCode, ppp, c.pppTTL(ppp))
 	}
 	
 	return ppp, nil
 }
 
 // GetExchangeRate fetches exchange rate between two currencies
+// Honours an as-of date set with ContextWithAsOf
 func (c *Client) GetExchangeRate(ctx context.Context, from, to string) (*ExchangeRate, error) {
+	if date, ok := AsOfFromContext(ctx); ok {
+		return c.getExchangeRateAsOf(ctx, from, to, date)
+	}
+	
 	// Check cache first if enabled
 	if c.cacheEnabled && c.cache != nil {
-		if rate, found := c.cache.GetExchangeRate(from, to); found {
+		rate, found := c.cache.GetExchangeRate(from, to)
+		c.observeCache(ctx, CacheFamilyRate, CacheKeyExchangeRate(from, to), found)
+		if found {
 			return rate, nil
 		}
 	}
@@ -119,15 +334,40 @@ func (c *Client) GetExchangeRate(ctx context.Context, from, to string) (*Exchang
 	
 	// Store in cache if enabled
 	if c.cacheEnabled && c.cache != nil {
-		// Exchange rates cache for shorter duration (1 hour)
-		c.cache.SetExchangeRate(from, to, rate, time.Hour)
+		c.cache.SetExchangeRate(from, to, rate, c.ttl(CacheFamilyRate))
 	}
 	
 	return rate, nil
 }
 
+// GetRateTable fetches all exchange rates from a base currency
+// The table is always fetched fresh; every pair in it is stored in the cache
+func (c *Client) GetRateTable(ctx context.Context, base string) (*RateTable, error) {
+	table, err := c.currency.GetRateTable(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Store each pair in cache if enabled
+	if c.cacheEnabled && c.cache != nil {
+		ttl := c.ttl(CacheFamilyRate)
+		for to, rate := range table.Rates {
+			c.cache.SetExchangeRate(table.Base, to, &ExchangeRate{
+				From:        table.Base,
+				To:          to,
+				Rate:        rate,
+				LastUpdated: table.LastUpdated,
+			}, ttl)
+		}
+	}
+	
+	return table, nil
+}
+
 // Recommend calculates recommended price based on PPP
 func (c *Client) Recommend(ctx context.Context, price float64, fromCurrency, toCountry string) (*PriceRecommendation, error) {
+	toCountry = canonicalCountry(toCountry)
+	
 	// Get PPP data
 	ppp, err := c.GetPPP(ctx, toCountry)
 	if err != nil {
@@ -155,7 +395,7 @@ func (c *Client) Recommend(ctx context.Context, price float64, fromCurrency, toC
 	normalPrice := price * rate.Rate
 	discountPercentage := ((normalPrice - recommendedPrice) / normalPrice) * 100
 	
-	return &PriceRecommendation{
+	recommendation := &PriceRecommendation{
 		OriginalPrice:      price,
 		OriginalCurrency:   fromCurrency,
 		RecommendedPrice:   recommendedPrice,
@@ -163,19 +403,73 @@ func (c *Client) Recommend(ctx context.Context, price float64, fromCurrency, toC
 		PPPFactor:          ppp.Factor,
 		ExchangeRate:       rate.Rate,
 		DiscountPercentage: discountPercentage,
+		PPPYear:            ppp.Year,
+	}
+	if !rate.LastUpdated.IsZero() {
+		date := rate.LastUpdated
+		recommendation.ExchangeRateDate = &date
+	}
+	
+	if date, ok := AsOfFromContext(ctx); ok {
+		recommendation.AsOf = &date
+	}
+	
+	return recommendation, nil
+}
+
+// RecommendMoney calculates recommended price based on PPP with exact Money amounts
+func (c *Client) RecommendMoney(ctx context.Context, price Money, toCountry string) (*MoneyRecommendation, error) {
+	rec, err := c.Recommend(ctx, price.Float64(), price.Currency, toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	recommended, err := price.Convert(rec.PPPFactor, rec.TargetCurrency, RoundHalfUp)
+	if err != nil {
+		return nil, err
+	}
+	
+	market, err := price.Convert(rec.ExchangeRate, rec.TargetCurrency, RoundHalfUp)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &MoneyRecommendation{
+		Original:            price,
+		Recommended:         recommended,
+		MarketPrice:         market,
+		PriceRecommendation: rec,
 	}, nil
 }
 
-// GetCountries fetches all available countries
+// GetCountries returns all available countries
+// Uses the embedded snapshot unless WithLiveCountries is set or a live list
+// was cached by RefreshCountries or a Warmer
 func (c *Client) GetCountries(ctx context.Context) ([]Country, error) {
 	// Check cache first if enabled
 	if c.cacheEnabled && c.cache != nil {
-		if countries, found := c.cache.GetCountries(); found {
+		countries, found := c.cache.GetCountries()
+		c.observeCache(ctx, CacheFamilyCountries, CacheKeyCountries(), found)
+		if found {
 			return countries, nil
 		}
 	}
 	
-	// Fetch from API
+	if !c.liveCountries {
+		return offlineCountries(), nil
+	}
+	
+	return c.fetchCountries(ctx)
+}
+
+// RefreshCountries fetches the live World Bank country list and caches it,
+// so GetCountries returns it until the countries TTL expires
+func (c *Client) RefreshCountries(ctx context.Context) ([]Country, error) {
+	return c.fetchCountries(ctx)
+}
+
+// fetchCountries fetches the country list from the API, bypassing the cache, and stores the result
+func (c *Client) fetchCountries(ctx context.Context) ([]Country, error) {
 	countries, err := c.worldBank.GetCountries(ctx)
 	if err != nil {
 		return nil, err
@@ -183,8 +477,7 @@ func (c *Client) GetCountries(ctx context.Context) ([]Country, error) {
 	
 	// Store in cache if enabled
 	if c.cacheEnabled && c.cache != nil {
-		// Countries don't change often, cache for longer
-		c.cache.SetCountries(countries, 7*24*time.Hour)
+		c.cache.SetCountries(countries, c.ttl(CacheFamilyCountries))
 	}
 	
 	return countries, nil
@@ -194,7 +487,9 @@ func (c *Client) GetCountries(ctx context.Context) ([]Country, error) {
 func (c *Client) SearchIndicators(ctx context.Context, search string) ([]Indicator, error) {
 	// Check cache first if enabled
 	if c.cacheEnabled && c.cache != nil {
-		if indicators, found := c.cache.GetIndicators(search); found {
+		indicators, found := c.cache.GetIndicators(search)
+		c.observeCache(ctx, CacheFamilyIndicators, CacheKeyIndicators(search), found)
+		if found {
 			return indicators, nil
 		}
 	}
@@ -207,31 +502,39 @@ func (c *Client) SearchIndicators(ctx context.Context, search string) ([]Indicat
 	
 	// Store in cache if enabled
 	if c.cacheEnabled && c.cache != nil {
-		c.cache.SetIndicators(search, indicators, 24*time.Hour)
+		c.cache.SetIndicators(search, indicators, c.ttl(CacheFamilyIndicators))
 	}
 	
 	return indicators, nil
 }
 
+// ValidateDateRange validates year range against the client's clock
+func (c *Client) ValidateDateRange(startYear, endYear int) error {
+	return validateDateRange(startYear, endYear, c.now().Year())
+}
+
 // GetHistoricalPPP fetches historical PPP data
+// Alpha-3, numeric and lowercase codes are normalized to alpha-2
 func (c *Client) GetHistoricalPPP(ctx context.Context, countryCode string, startYear, endYear int) ([]PPPData, error) {
-	return c.worldBank.GetHistoricalPPP(ctx, countryCode, startYear, endYear)
+	return c.worldBank.GetHistoricalPPP(ctx, canonicalCountry(countryCode), startYear, endYear)
 }
 
 // GetIndicatorData fetches data for any indicator
 func (c *Client) GetIndicatorData(ctx context.Context, countryCode, indicatorCode string, startYear, endYear int) ([]IndicatorData, error) {
-	return c.worldBank.GetIndicatorData(ctx, countryCode, indicatorCode, startYear, endYear)
+	return c.worldBank.GetIndicatorData(ctx, canonicalCountry(countryCode), indicatorCode, startYear, endYear)
 }
 
 // AnalyzePPPTrend analyzes PPP trend for a country
 func (c *Client) AnalyzePPPTrend(ctx context.Context, countryCode string, startYear, endYear int) (*PPPTrendAnalysis, error) {
+	countryCode = canonicalCountry(countryCode)
 	data, err := c.GetHistoricalPPP(ctx, countryCode, startYear, endYear)
 	if err != nil {
 		return nil, err
 	}
 	
 	if len(data) == 0 {
-		return nil, fmt.Errorf("no data available for analysis")
+		return nil, NewPPPError(ErrCodeNoData, "no data available for analysis", ErrNoData).
+			WithContext("country", countryCode)
 	}
 	
 	// Calculate average
@@ -281,6 +584,7 @@ func (c *Client) ComparePPP(ctx context.Context, countryCodes []string) ([]Count
 	comparisons := make([]CountryComparison, 0, len(countryCodes))
 	
 	for i, code := range countryCodes {
+		code = canonicalCountry(code)
 		ppp, err := c.GetPPP(ctx, code)
 		if err != nil {
 			continue // Skip countries with errors
@@ -336,58 +640,94 @@ func (c *Client) ClearCache() {
 	}
 }
 
+// CacheStats returns cache lookup counters keyed by family
+func (c *Client) CacheStats() (map[string]CacheStats, error) {
+	if !c.cacheEnabled || c.cache == nil {
+		return nil, ErrCacheDisabled
+	}
+	return c.cache.Stats(), nil
+}
+
+// CacheEntries lists the items currently held in the cache
+func (c *Client) CacheEntries() ([]CacheEntry, error) {
+	if !c.cacheEnabled || c.cache == nil {
+		return nil, ErrCacheDisabled
+	}
+	return c.cache.Entries(), nil
+}
+
+// InvalidateCountry removes cached PPP data for a country
+// Returns the number of removed entries
+func (c *Client) InvalidateCountry(countryCode string) int {
+	if !c.cacheEnabled || c.cache == nil {
+		return 0
+	}
+	return c.cache.InvalidateCountry(countryCode)
+}
+
+// InvalidateCurrency removes cached exchange rates involving a currency
+// Returns the number of removed entries
+func (c *Client) InvalidateCurrency(currencyCode string) int {
+	if !c.cacheEnabled || c.cache == nil {
+		return 0
+	}
+	return c.cache.InvalidateCurrency(currencyCode)
+}
+
+// InvalidateCachePrefix removes cached entries whose key starts with prefix
+// Returns the number of removed entries
+func (c *Client) InvalidateCachePrefix(prefix string) int {
+	if !c.cacheEnabled || c.cache == nil {
+		return 0
+	}
+	return c.cache.InvalidatePrefix(prefix)
+}
+
+// ttl returns how long entries of a cache family are kept
+// Family options win over WithCache, which wins over the defaults
+func (c *Client) ttl(family string) time.Duration {
+	if ttl, ok := c.familyTTLs[family]; ok && ttl > 0 {
+		return ttl
+	}
+	
+	if c.cacheTTLSet {
+		return c.cacheDuration
+	}
+	
+	switch family {
+	case CacheFamilyPPP:
+		return c.pppRelease.TTL(c.now(), nil)
+	case CacheFamilyRate:
+		// Exchange rates cache for shorter duration
+		return time.Hour
+	case CacheFamilyCountries:
+		// Countries don't change often, cache for longer
+		return 7 * 24 * time.Hour
+	case CacheFamilyIndicators:
+		return 24 * time.Hour
+	default:
+		return c.cacheDuration
+	}
+}
+
+// pppTTL returns how long a PPP value should be cached
+func (c *Client) pppTTL(data *PPPData) time.Duration {
+	if c.familyTTLs[CacheFamilyPPP] > 0 || c.cacheTTLSet {
+		return c.ttl(CacheFamilyPPP)
+	}
+	return c.pppRelease.TTL(c.now(), data)
+}
+
 // getCurrencyForCountry maps country code to currency code
-// This is a simplified mapping - in production you'd want a complete list
+// Uses the embedded country metadata, then the ISO 4217 registry when a
+// country has a single currency, and defaults to USD
 func (c *Client) getCurrencyForCountry(countryCode string) string {
-	currencyMap := map[string]string{
-		"US": "USD",
-		"TR": "TRY",
-		"DE": "EUR",
-		"FR": "EUR",
-		"IT": "EUR",
-		"ES": "EUR",
-		"GB": "GBP",
-		"JP": "JPY",
-		"CN": "CNY",
-		"IN": "INR",
-		"BR": "BRL",
-		"RU": "RUB",
-		"CA": "CAD",
-		"AU": "AUD",
-		"MX": "MXN",
-		"KR": "KRW",
-		"ID": "IDR",
-		"SA": "SAR",
-		"AR": "ARS",
-		"ZA": "ZAR",
-		"NG": "NGN",
-		"EG": "EGP",
-		"PK": "PKR",
-		"BD": "BDT",
-		"VN": "VND",
-		"TH": "THB",
-		"MY": "MYR",
-		"SG": "SGD",
-		"PH": "PHP",
-		"NZ": "NZD",
-		"CH": "CHF",
-		"SE": "SEK",
-		"NO": "NOK",
-		"DK": "DKK",
-		"PL": "PLN",
-		"CZ": "CZK",
-		"HU": "HUF",
-		"RO": "RON",
-		"UA": "UAH",
-		"IL": "ILS",
-		"AE": "AED",
-		"CL": "CLP",
-		"CO": "COP",
-		"PE": "PEN",
-	}
-	
-	if currency, ok := currencyMap[countryCode]; ok {
-		return currency
+	if m, ok := LookupCountryMetadata(countryCode); ok {
+		return m.Currency
+	}
+	
+	if list := CurrenciesForCountry(countryCode); len(list) == 1 {
+		return list[0].Code
 	}
 	
 	// Default to USD if not found
@@ -0,0 +1,30 @@
+package ppp
+
+import (
+	"time"
+)
+
+// Clock tells the library what time it is
+// It governs date windows, data timestamps and cache expiry, so a client can
+// run "as of" any date. Timers, retries and rate limiting use wall time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface
+type ClockFunc func() time.Time
+
+// Now implements Clock
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the system time
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a clock that always reports t
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time {
+		return t
+	})
+}
@@ -0,0 +1,68 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientClock(t *testing.T) {
+	var dates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dates = append(dates, r.URL.Query().Get("date"))
+		fmt.Fprint(w, `[{"page":1},[{"country":{"id":"TR","value":"Turkiye"},"date":"2019","value":1.93}]]`)
+	}))
+	defer server.Close()
+
+	asOf := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient(WithWorldBankURL(server.URL), WithClock(FixedClock(asOf)))
+
+	ppp, err := client.GetPPP(context.Background(), "TR")
+	if err != nil {
+		t.Fatalf("GetPPP failed: %v", err)
+	}
+	if len(dates) != 1 || dates[0] != "2010:2020" {
+		t.Errorf("Expected date window 2010:2020, got %v", dates)
+	}
+	if !ppp.LastUpdated.Equal(asOf) {
+		t.Errorf("LastUpdated = %v, want %v", ppp.LastUpdated, asOf)
+	}
+}
+
+func TestClientValidateDateRange(t *testing.T) {
+	client := NewClient(WithClock(FixedClock(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))))
+	if err := client.ValidateDateRange(2015, 2021); err != nil {
+		t.Errorf("Expected next year to be allowed, got %v", err)
+	}
+	if err := client.ValidateDateRange(2015, 2022); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("Expected ErrInvalidDateRange on the client's clock, got %v", err)
+	}
+}
+
+func TestCacheClockExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(time.Hour, 2*time.Hour)
+	cache.SetClock(ClockFunc(func() time.Time { return now }))
+
+	cache.SetPPP("TR", &PPPData{CountryCode: "TR", Factor: 7.35}, time.Hour)
+
+	now = now.Add(30 * time.Minute)
+	if _, found := cache.GetPPP("TR"); !found {
+		t.Fatal("Expected entry to be fresh after 30 minutes")
+	}
+	if entries := cache.Entries(); len(entries) != 1 || entries[0].Age != 30*time.Minute {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	now = now.Add(time.Hour)
+	if _, found := cache.GetPPP("TR"); found {
+		t.Error("Expected entry to expire on the cache clock")
+	}
+	if got := cache.Stats()[CacheFamilyPPP].Evictions; got != 1 {
+		t.Errorf("Evictions = %d, want 1", got)
+	}
+}
@@ -0,0 +1,127 @@
+// Command ppp-server serves PPP pricing as a JSON HTTP API
+//
+// Endpoints:
+//
+//	GET  /v1/recommend?price=100&currency=USD&country=TR
+//	POST /v1/batch      {"price": 100, "currency": "USD", "countries": ["TR", "IN"]}
+//	GET  /v1/factor?country=TR
+//	GET  /v1/rate?from=USD&to=TRY
+//	GET  /v1/countries
+//	GET  /v1/compare?countries=TR,DE,US
+//	GET  /v1/trend?country=TR&start=2015&end=2022
+//	GET  /healthz, /readyz
+//
+// Invalid input returns 400, missing data 404, rate limiting 429 and
+// upstream failures 502, 503 or 504. Errors have the form
+// {"error": {"code": "INVALID_INPUT", "message": "..."}}
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("ppp-server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		addr            = flag.String("addr", ":8080", "listen address")
+		cacheTTL        = flag.Duration("cache", 24*time.Hour, "cache duration, 0 to disable caching")
+		upstreamTimeout = flag.Duration("upstream-timeout", 30*time.Second, "timeout for World Bank and currency API calls")
+		requestTimeout  = flag.Duration("request-timeout", 30*time.Second, "timeout for each API request")
+		shutdownTimeout = flag.Duration("shutdown-timeout", 15*time.Second, "time to finish in-flight requests on shutdown")
+		liveCountries   = flag.Bool("live-countries", false, "fetch the country list from the World Bank instead of the embedded snapshot")
+		warm            = flag.Bool("warm", false, "preload PPP data and exchange rates before reporting ready")
+		worldBankURL    = flag.String("worldbank-url", "", "World Bank API base URL")
+		currencyURL     = flag.String("currency-url", "", "currency API base URL")
+		verbose         = flag.Bool("v", false, "log every request")
+	)
+	flag.Parse()
+	
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+	
+	opts := []ppp.Option{
+		ppp.WithTimeout(*upstreamTimeout),
+		ppp.WithObserver(ppp.NewSlogObserver(logger)),
+	}
+	if *cacheTTL > 0 {
+		opts = append(opts, ppp.WithCache(*cacheTTL))
+	} else {
+		opts = append(opts, ppp.WithoutCache())
+	}
+	if *liveCountries {
+		opts = append(opts, ppp.WithLiveCountries())
+	}
+	if *worldBankURL != "" {
+		opts = append(opts, ppp.WithWorldBankURL(*worldBankURL))
+	}
+	if *currencyURL != "" {
+		opts = append(opts, ppp.WithCurrencyURL(*currencyURL))
+	}
+	client := ppp.NewClient(opts...)
+	
+	srv := newServer(client, logger, *requestTimeout)
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      *requestTimeout + 10*time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", *addr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+	
+	if *warm {
+		if err := ppp.NewWarmer(client).Warm(ctx); err != nil {
+			logger.Warn("cache warming incomplete", "error", err)
+		}
+	}
+	srv.ready.Store(true)
+	
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	
+	// Fail readiness first so load balancers stop sending traffic
+	logger.Info("shutting down")
+	srv.ready.Store(false)
+	
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
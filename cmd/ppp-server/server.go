@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// maxBatchCountries bounds the countries of a batch request
+const maxBatchCountries = 300
+
+// maxBodyBytes bounds request bodies
+const maxBodyBytes = 1 << 20
+
+// server serves the pricing API
+type server struct {
+	client  *ppp.Client
+	logger  *slog.Logger
+	timeout time.Duration
+	ready   atomic.Bool
+}
+
+// newServer creates a server for a client
+func newServer(client *ppp.Client, logger *slog.Logger, timeout time.Duration) *server {
+	return &server{client: client, logger: logger, timeout: timeout}
+}
+
+// routes returns the HTTP handler with all endpoints
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	mux.Handle("/v1/recommend", s.get(s.handleRecommend))
+	mux.Handle("/v1/batch", s.endpoint(s.handleBatch, http.MethodGet, http.MethodPost))
+	mux.Handle("/v1/factor", s.get(s.handleFactor))
+	mux.Handle("/v1/rate", s.get(s.handleRate))
+	mux.Handle("/v1/countries", s.get(s.handleCountries))
+	mux.Handle("/v1/compare", s.get(s.handleCompare))
+	mux.Handle("/v1/trend", s.get(s.handleTrend))
+	return mux
+}
+
+// apiHandler is an endpoint returning a JSON value or an error
+type apiHandler func(r *http.Request) (interface{}, error)
+
+// get allows GET and HEAD requests
+func (s *server) get(h apiHandler) http.Handler {
+	return s.endpoint(h, http.MethodGet, http.MethodHead)
+}
+
+// endpoint adapts an apiHandler, enforcing methods and the request timeout
+func (s *server) endpoint(h apiHandler, methods ...string) http.Handler {
+	allow := strings.Join(methods, ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed := false
+		for _, m := range methods {
+			allowed = allowed || r.Method == m
+		}
+		if !allowed {
+			w.Header().Set("Allow", allow)
+			writeJSON(w, http.StatusMethodNotAllowed, errorBody("METHOD_NOT_ALLOWED", r.Method+" is not allowed"))
+			return
+		}
+		
+		ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+		defer cancel()
+		
+		start := time.Now()
+		value, err := h(r.WithContext(ctx))
+		if err != nil {
+			status := statusFor(err)
+			if status >= http.StatusInternalServerError {
+				s.logger.Error("request failed", "path", r.URL.Path, "status", status, "error", err)
+			}
+			writeError(w, status, err)
+			return
+		}
+		
+		s.logger.Debug("request served", "path", r.URL.Path, "duration", time.Since(start))
+		writeJSON(w, http.StatusOK, value)
+	})
+}
+
+// handleHealth reports that the process is alive
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether the server accepts traffic
+// It fails while starting up and once shutdown has begun
+func (s *server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// handleRecommend serves GET /v1/recommend?price=100&currency=USD&country=TR[&as_of=2024-01-31]
+func (s *server) handleRecommend(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	price, err := amountParam(q.Get("price"))
+	if err != nil {
+		return nil, err
+	}
+	currency, err := currencyParam(q.Get("currency"))
+	if err != nil {
+		return nil, err
+	}
+	country, err := ppp.NormalizeCountryCode(q.Get("country"))
+	if err != nil {
+		return nil, err
+	}
+	
+	if asOf := q.Get("as_of"); asOf != "" {
+		date, err := time.Parse("2006-01-02", asOf)
+		if err != nil {
+			return nil, invalidInput("as_of must be a date like 2024-01-31", err)
+		}
+		return s.client.RecommendAsOf(r.Context(), date, price, currency, country)
+	}
+	return s.client.Recommend(r.Context(), price, currency, country)
+}
+
+// batchRequest is the body of POST /v1/batch
+type batchRequest struct {
+	Price     float64  `json:"price"`
+	Currency  string   `json:"currency"`
+	Countries []string `json:"countries"`
+}
+
+// batchResponse holds per-country results; failures don't fail the batch
+type batchResponse struct {
+	Results map[string]*ppp.PriceRecommendation `json:"results"`
+	Errors  map[string]apiError                 `json:"errors,omitempty"`
+}
+
+// handleBatch serves POST /v1/batch with a JSON body, or
+// GET /v1/batch?price=100&currency=USD&countries=TR,BR,IN
+func (s *server) handleBatch(r *http.Request) (interface{}, error) {
+	var req batchRequest
+	if r.Method == http.MethodPost {
+		decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodyBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return nil, invalidInput("invalid JSON body", err)
+		}
+	} else {
+		q := r.URL.Query()
+		price, err := amountParam(q.Get("price"))
+		if err != nil {
+			return nil, err
+		}
+		req = batchRequest{Price: price, Currency: q.Get("currency"), Countries: listParam(q.Get("countries"))}
+	}
+	
+	if err := ppp.ValidateAmount(req.Price); err != nil {
+		return nil, err
+	}
+	currency, err := currencyParam(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Countries) == 0 || len(req.Countries) > maxBatchCountries {
+		return nil, invalidInput(fmt.Sprintf("countries must list 1 to %d countries", maxBatchCountries), nil)
+	}
+	
+	resp := batchResponse{
+		Results: make(map[string]*ppp.PriceRecommendation, len(req.Countries)),
+		Errors:  make(map[string]apiError),
+	}
+	for _, code := range req.Countries {
+		country, err := ppp.NormalizeCountryCode(code)
+		if err == nil {
+			var rec *ppp.PriceRecommendation
+			if rec, err = s.client.Recommend(r.Context(), req.Price, currency, country); err == nil {
+				resp.Results[code] = rec
+				continue
+			}
+		}
+		if ctxErr := r.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		resp.Errors[code] = newAPIError(err)
+	}
+	return resp, nil
+}
+
+// handleFactor serves GET /v1/factor?country=TR
+func (s *server) handleFactor(r *http.Request) (interface{}, error) {
+	country, err := ppp.NormalizeCountryCode(r.URL.Query().Get("country"))
+	if err != nil {
+		return nil, err
+	}
+	return s.client.GetPPP(r.Context(), country)
+}
+
+// handleRate serves GET /v1/rate?from=USD&to=TRY
+func (s *server) handleRate(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	from, err := currencyParam(q.Get("from"))
+	if err != nil {
+		return nil, err
+	}
+	to, err := currencyParam(q.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	return s.client.GetExchangeRate(r.Context(), from, to)
+}
+
+// handleCountries serves GET /v1/countries
+func (s *server) handleCountries(r *http.Request) (interface{}, error) {
+	return s.client.GetCountries(r.Context())
+}
+
+// handleCompare serves GET /v1/compare?countries=TR,DE,US
+func (s *server) handleCompare(r *http.Request) (interface{}, error) {
+	codes := listParam(r.URL.Query().Get("countries"))
+	if len(codes) < 2 || len(codes) > maxBatchCountries {
+		return nil, invalidInput(fmt.Sprintf("countries must list 2 to %d countries", maxBatchCountries), nil)
+	}
+	for i, code := range codes {
+		country, err := ppp.NormalizeCountryCode(code)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = country
+	}
+	return s.client.ComparePPP(r.Context(), codes)
+}
+
+// handleTrend serves GET /v1/trend?country=TR&start=2015&end=2022
+func (s *server) handleTrend(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	country, err := ppp.NormalizeCountryCode(q.Get("country"))
+	if err != nil {
+		return nil, err
+	}
+	start, err := yearParam(q.Get("start"), "start")
+	if err != nil {
+		return nil, err
+	}
+	end, err := yearParam(q.Get("end"), "end")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.client.ValidateDateRange(start, end); err != nil {
+		return nil, err
+	}
+	return s.client.AnalyzePPPTrend(r.Context(), country, start, end)
+}
+
+// amountParam parses and validates a price
+func amountParam(value string) (float64, error) {
+	if value == "" {
+		return 0, invalidInput("price is required", ppp.ErrInvalidAmount)
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, invalidInput("price must be a number", ppp.ErrInvalidAmount)
+	}
+	return price, ppp.ValidateAmount(price)
+}
+
+// currencyParam validates a currency code and returns it in upper case
+func currencyParam(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	return currency, ppp.ValidateCurrencyCode(currency)
+}
+
+// yearParam parses a year
+func yearParam(value, name string) (int, error) {
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, invalidInput(name+" must be a year", ppp.ErrInvalidDateRange)
+	}
+	return year, nil
+}
+
+// listParam splits a comma separated list, dropping empty items
+func listParam(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// invalidInput returns an INVALID_INPUT error
+func invalidInput(message string, err error) error {
+	return ppp.NewPPPError(ppp.ErrCodeInvalidInput, message, err)
+}
+
+// statusFor maps an error to an HTTP status code using PPPError codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		// The client went away; nginx's "client closed request"
+		return 499
+	case errors.Is(err, ppp.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	}
+	
+	var pppErr *ppp.PPPError
+	if !errors.As(err, &pppErr) {
+		// Failures below the client are upstream API errors
+		return http.StatusBadGateway
+	}
+	
+	switch pppErr.Code {
+	case ppp.ErrCodeInvalidInput:
+		return http.StatusBadRequest
+	case ppp.ErrCodeNoData:
+		return http.StatusNotFound
+	case ppp.ErrCodeRateLimit:
+		return http.StatusTooManyRequests
+	case ppp.ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	case ppp.ErrCodeAPIError, ppp.ErrCodeNetworkError:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// apiError is the JSON form of an error
+type apiError struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+// newAPIError converts an error, keeping PPPError codes and context
+func newAPIError(err error) apiError {
+	var pppErr *ppp.PPPError
+	if errors.As(err, &pppErr) {
+		return apiError{Code: pppErr.Code, Message: pppErr.Message, Context: pppErr.Context}
+	}
+	switch statusFor(err) {
+	case http.StatusGatewayTimeout:
+		return apiError{Code: ppp.ErrCodeTimeout, Message: err.Error()}
+	case http.StatusBadGateway:
+		return apiError{Code: ppp.ErrCodeAPIError, Message: err.Error()}
+	}
+	return apiError{Code: "INTERNAL", Message: err.Error()}
+}
+
+// errorBody wraps an error code and message for a response
+func errorBody(code, message string) map[string]apiError {
+	return map[string]apiError{"error": {Code: code, Message: message}}
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]apiError{"error": newAPIError(err)})
+}
+
+// writeJSON writes a JSON response
+// The value is encoded before the status is sent, so values that can't be
+// encoded become a 500 instead of an empty response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(value); err != nil {
+		slog.Default().Error("failed to encode response", "error", err)
+		status = http.StatusInternalServerError
+		buf.Reset()
+		json.NewEncoder(&buf).Encode(errorBody("INTERNAL", "failed to encode response"))
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.Default().Debug("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+func newTestServer(t *testing.T) (*server, *httptest.Server) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := newServer(ppptest.NewClient(t), logger, 5*time.Second)
+	ts := httptest.NewServer(srv.routes())
+	t.Cleanup(ts.Close)
+	return srv, ts
+}
+
+// do sends a request and decodes the JSON response into out
+func do(t *testing.T, method, url, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s %s: Content-Type = %q", method, url, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: invalid JSON: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+func TestEndpoints(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	var rec ppp.PriceRecommendation
+	if status := do(t, http.MethodGet, ts.URL+"/v1/recommend?price=100&currency=usd&country=TUR", "", &rec); status != http.StatusOK {
+		t.Fatalf("recommend status = %d", status)
+	}
+	if rec.TargetCurrency != "TRY" || math.Abs(rec.RecommendedPrice-1155) > 1e-9 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+
+	var factor ppp.PPPData
+	if status := do(t, http.MethodGet, ts.URL+"/v1/factor?country=tr", "", &factor); status != http.StatusOK || factor.Factor != 11.55 {
+		t.Errorf("factor = %d %+v", status, factor)
+	}
+
+	var rate ppp.ExchangeRate
+	if status := do(t, http.MethodGet, ts.URL+"/v1/rate?from=USD&to=TRY", "", &rate); status != http.StatusOK || rate.Rate != 40.47 {
+		t.Errorf("rate = %d %+v", status, rate)
+	}
+
+	var countries []ppp.Country
+	if status := do(t, http.MethodGet, ts.URL+"/v1/countries", "", &countries); status != http.StatusOK || len(countries) < 100 {
+		t.Errorf("countries = %d, %d countries", status, len(countries))
+	}
+
+	var comparison []ppp.CountryComparison
+	if status := do(t, http.MethodGet, ts.URL+"/v1/compare?countries=TR,DE,US", "", &comparison); status != http.StatusOK || len(comparison) != 3 {
+		t.Errorf("compare = %d %+v", status, comparison)
+	}
+
+	var trend ppp.PPPTrendAnalysis
+	if status := do(t, http.MethodGet, ts.URL+"/v1/trend?country=TR&start=2020&end=2022", "", &trend); status != http.StatusOK || trend.DataPoints != 3 {
+		t.Errorf("trend = %d %+v", status, trend)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	var resp batchResponse
+	body := `{"price": 100, "currency": "USD", "countries": ["TR", "tr", "ZZ"]}`
+	if status := do(t, http.MethodPost, ts.URL+"/v1/batch", body, &resp); status != http.StatusOK {
+		t.Fatalf("batch status = %d", status)
+	}
+	if len(resp.Results) != 2 || resp.Results["tr"] == nil || resp.Results["TR"].RecommendedPrice != resp.Results["tr"].RecommendedPrice {
+		t.Errorf("Unexpected results %+v", resp.Results)
+	}
+	if resp.Errors["ZZ"].Code != ppp.ErrCodeInvalidInput {
+		t.Errorf("Expected INVALID_INPUT for ZZ, got %+v", resp.Errors)
+	}
+
+	resp = batchResponse{}
+	if status := do(t, http.MethodGet, ts.URL+"/v1/batch?price=100&currency=USD&countries=TR,+IN", "", &resp); status != http.StatusOK || len(resp.Results) != 2 {
+		t.Errorf("GET batch = %d %+v", status, resp)
+	}
+
+	var errResp errorResponse
+	if status := do(t, http.MethodPost, ts.URL+"/v1/batch", `{"price": 100, "currency": "USD"}`, &errResp); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 without countries, got %d", status)
+	}
+	if status := do(t, http.MethodPost, ts.URL+"/v1/batch", `{"price": "x"}`, &errResp); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid body, got %d", status)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{http.MethodGet, "/v1/recommend?currency=USD&country=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=-1&currency=USD&country=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=NaN&currency=USD&country=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=Inf&currency=USD&country=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=100&currency=DOLLARS&country=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=100&currency=USD&country=ZZ", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/recommend?price=100&currency=USD&country=TR&as_of=yesterday", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/rate?from=USD&to=AFN", http.StatusNotFound, ppp.ErrCodeNoData},
+		{http.MethodGet, "/v1/compare?countries=TR", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/trend?country=TR&start=2022&end=2020", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodGet, "/v1/trend?country=TR&start=x&end=2020", http.StatusBadRequest, ppp.ErrCodeInvalidInput},
+		{http.MethodPost, "/v1/factor?country=TR", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+	}
+
+	for _, tt := range tests {
+		var resp errorResponse
+		status := do(t, tt.method, ts.URL+tt.path, "", &resp)
+		if status != tt.status || resp.Error.Code != tt.code {
+			t.Errorf("%s %s = %d %s, want %d %s", tt.method, tt.path, status, resp.Error.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestWriteJSONEncodingFailure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeJSON(rec, http.StatusOK, map[string]float64{"price": math.NaN()})
+
+	var resp errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if rec.Code != http.StatusInternalServerError || resp.Error.Code != "INTERNAL" {
+		t.Errorf("Expected a 500 INTERNAL error, got %d %+v", rec.Code, resp.Error)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{ppp.NewPPPError(ppp.ErrCodeInvalidInput, "bad", nil), http.StatusBadRequest},
+		{fmt.Errorf("wrapped: %w", ppp.NewPPPError(ppp.ErrCodeNoData, "none", ppp.ErrNoData)), http.StatusNotFound},
+		{ppp.NewPPPError(ppp.ErrCodeRateLimit, "slow down", ppp.ErrRateLimited), http.StatusTooManyRequests},
+		{ppp.NewPPPError(ppp.ErrCodeAPIError, "circuit open", ppp.ErrCircuitOpen), http.StatusServiceUnavailable},
+		{ppp.NewPPPError(ppp.ErrCodeTimeout, "slow", nil), http.StatusGatewayTimeout},
+		{ppp.NewPPPError(ppp.ErrCodeCacheError, "cache", nil), http.StatusInternalServerError},
+		{fmt.Errorf("failed: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{errors.New("API returned status 500"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		if got := statusFor(tt.err); got != tt.status {
+			t.Errorf("statusFor(%v) = %d, want %d", tt.err, got, tt.status)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	srv, ts := newTestServer(t)
+
+	if status := do(t, http.MethodGet, ts.URL+"/healthz", "", nil); status != http.StatusOK {
+		t.Errorf("healthz = %d", status)
+	}
+	if status := do(t, http.MethodGet, ts.URL+"/readyz", "", nil); status != http.StatusServiceUnavailable {
+		t.Errorf("Expected readyz to fail before startup completes, got %d", status)
+	}
+	srv.ready.Store(true)
+	if status := do(t, http.MethodGet, ts.URL+"/readyz", "", nil); status != http.StatusOK {
+		t.Errorf("readyz = %d", status)
+	}
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// recommendation is a PriceRecommendation with the country it is for
+type recommendation struct {
+	Country string `json:"country"`
+	*ppp.PriceRecommendation
+}
+
+// recommendationHeader lists the columns of recommendationRow
+var recommendationHeader = []string{
+	"country", "original_price", "original_currency", "recommended_price",
+	"target_currency", "ppp_factor", "exchange_rate", "discount_percentage", "ppp_year",
+}
+
+// recommendationRow returns the table row of a recommendation
+func recommendationRow(r recommendation) []string {
+	return []string{
+		r.Country,
+		number(r.OriginalPrice),
+		r.OriginalCurrency,
+		number(ppp.RoundPrice(r.RecommendedPrice, r.TargetCurrency)),
+		r.TargetCurrency,
+		number(r.PPPFactor),
+		number(r.ExchangeRate),
+		fixed(r.DiscountPercentage, 1),
+		strconv.Itoa(r.PPPYear),
+	}
+}
+
+// pricingInput holds the arguments of recommend and batch
+type pricingInput struct {
+	price     float64
+	currency  string
+	countries []string
+	asOf      time.Time
+}
+
+// parsePricing parses the flags and arguments of recommend and batch
+func parsePricing(c *cli, name string, args []string, multiple bool) (*pricingInput, error) {
+	fs := commandFlags(c, name)
+	asOf := fs.String("as-of", "", "price with the data published by this date (YYYY-MM-DD)")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	args = fs.Args()
+	if len(args) < 3 {
+		return nil, usageError("expected a price, a currency and a country")
+	}
+	if !multiple && len(args) > 3 {
+		return nil, usageError("expected a single country, use batch for several")
+	}
+	
+	in := &pricingInput{currency: strings.ToUpper(args[1])}
+	price, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return nil, ppp.NewPPPError(ppp.ErrCodeInvalidInput, fmt.Sprintf("price %q is not a number", args[0]), ppp.ErrInvalidAmount)
+	}
+	in.price = price
+	if err := ppp.ValidateAmount(in.price); err != nil {
+		return nil, err
+	}
+	if err := ppp.ValidateCurrencyCode(in.currency); err != nil {
+		return nil, err
+	}
+	if in.countries, err = countryArgs(args[2:]); err != nil {
+		return nil, err
+	}
+	if len(in.countries) == 0 {
+		return nil, usageError("expected a country")
+	}
+	if !multiple && len(in.countries) > 1 {
+		return nil, usageError("expected a single country, use batch for several")
+	}
+	if *asOf != "" {
+		if in.asOf, err = time.Parse("2006-01-02", *asOf); err != nil {
+			return nil, ppp.NewPPPError(ppp.ErrCodeInvalidInput, "as-of must be a date like 2024-01-31", ppp.ErrInvalidDateRange)
+		}
+	}
+	return in, nil
+}
+
+// recommend prices a product for a country, as of a date if one is set
+func (c *cli) recommend(ctx context.Context, in *pricingInput, country string) (recommendation, error) {
+	var (
+		rec *ppp.PriceRecommendation
+		err error
+	)
+	if in.asOf.IsZero() {
+		rec, err = c.client.Recommend(ctx, in.price, in.currency, country)
+	} else {
+		rec, err = c.client.RecommendAsOf(ctx, in.asOf, in.price, in.currency, country)
+	}
+	return recommendation{Country: country, PriceRecommendation: rec}, err
+}
+
+// runRecommend prints the PPP-adjusted price for one country
+func runRecommend(ctx context.Context, c *cli, args []string) (*result, error) {
+	in, err := parsePricing(c, "recommend", args, false)
+	if err != nil {
+		return nil, err
+	}
+	
+	rec, err := c.recommend(ctx, in, in.countries[0])
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value:  rec,
+		header: recommendationHeader,
+		rows:   [][]string{recommendationRow(rec)},
+	}, nil
+}
+
+// runBatch prints PPP-adjusted prices for several countries
+// Countries that fail are reported after the others are printed
+func runBatch(ctx context.Context, c *cli, args []string) (*result, error) {
+	in, err := parsePricing(c, "batch", args, true)
+	if err != nil {
+		return nil, err
+	}
+	
+	res := &result{header: recommendationHeader}
+	recs := make([]recommendation, 0, len(in.countries))
+	var errs []error
+	for _, country := range in.countries {
+		rec, err := c.recommend(ctx, in, country)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", country, err))
+			continue
+		}
+		recs = append(recs, rec)
+		res.rows = append(res.rows, recommendationRow(rec))
+	}
+	res.value = recs
+	
+	return res, errors.Join(errs...)
+}
+
+// runFactor prints the latest PPP factor of each country
+func runFactor(ctx context.Context, c *cli, args []string) (*result, error) {
+	countries, err := countryArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(countries) == 0 {
+		return nil, usageError("expected at least one country")
+	}
+	
+	res := &result{header: []string{"country_code", "country_name", "year", "factor", "source"}}
+	factors := make([]*ppp.PPPData, 0, len(countries))
+	var errs []error
+	for _, country := range countries {
+		data, err := c.client.GetPPP(ctx, country)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", country, err))
+			continue
+		}
+		factors = append(factors, data)
+		res.rows = append(res.rows, []string{data.CountryCode, data.CountryName, strconv.Itoa(data.Year), number(data.Factor), data.Source})
+	}
+	res.value = factors
+	
+	return res, errors.Join(errs...)
+}
+
+// runRate prints exchange rates from one currency to others
+func runRate(ctx context.Context, c *cli, args []string) (*result, error) {
+	codes := make([]string, 0, len(args))
+	for _, arg := range args {
+		for _, code := range strings.Split(arg, ",") {
+			if code = strings.ToUpper(strings.TrimSpace(code)); code == "" {
+				continue
+			}
+			if err := ppp.ValidateCurrencyCode(code); err != nil {
+				return nil, err
+			}
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) < 2 {
+		return nil, usageError("expected a base currency and at least one target currency")
+	}
+	
+	res := &result{header: []string{"from", "to", "rate", "last_updated"}}
+	rates := make([]*ppp.ExchangeRate, 0, len(codes)-1)
+	var errs []error
+	for _, to := range codes[1:] {
+		rate, err := c.client.GetExchangeRate(ctx, codes[0], to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", codes[0], to, err))
+			continue
+		}
+		rates = append(rates, rate)
+		res.rows = append(res.rows, []string{rate.From, rate.To, number(rate.Rate), rate.LastUpdated.Format("2006-01-02")})
+	}
+	res.value = rates
+	
+	return res, errors.Join(errs...)
+}
+
+// runCountries lists countries, optionally filtered by region or income level
+func runCountries(ctx context.Context, c *cli, args []string) (*result, error) {
+	fs := commandFlags(c, "countries")
+	region := fs.String("region", "", "only countries in this region, by name or ID")
+	income := fs.String("income", "", "only countries with this income level, by name or ID")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, usageError("unexpected argument %q", fs.Arg(0))
+	}
+	
+	countries, err := c.client.GetCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	res := &result{header: []string{"id", "iso2_code", "name", "region", "income_level", "capital_city"}}
+	matched := make([]ppp.Country, 0, len(countries))
+	for _, country := range countries {
+		if !matchesLevel(*region, country.Region.ID, country.Region.Value) ||
+			!matchesLevel(*income, country.IncomeLevel.ID, country.IncomeLevel.Value) {
+			continue
+		}
+		matched = append(matched, country)
+		res.rows = append(res.rows, []string{
+			country.ID, country.ISO2Code, country.Name,
+			country.Region.Value, country.IncomeLevel.Value, country.CapitalCity,
+		})
+	}
+	res.value = matched
+	
+	return res, nil
+}
+
+// matchesLevel reports whether a filter matches a World Bank level ID or
+// name; an empty filter matches everything
+func matchesLevel(filter, id, name string) bool {
+	return filter == "" || strings.EqualFold(filter, id) || strings.EqualFold(filter, name)
+}
+
+// runSearchIndicators searches World Bank indicators
+func runSearchIndicators(ctx context.Context, c *cli, args []string) (*result, error) {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return nil, usageError("expected a search query")
+	}
+	
+	indicators, err := c.client.SearchIndicators(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	
+	res := &result{value: indicators, header: []string{"id", "name", "source"}}
+	for _, indicator := range indicators {
+		res.rows = append(res.rows, []string{indicator.ID, indicator.Name, indicator.Source.Value})
+	}
+	return res, nil
+}
+
+// runHistory prints a country's PPP factor for each year of a range
+func runHistory(ctx context.Context, c *cli, args []string) (*result, error) {
+	if len(args) != 3 {
+		return nil, usageError("expected a country, a start year and an end year")
+	}
+	countries, err := countryArgs(args[:1])
+	if err != nil {
+		return nil, err
+	}
+	if len(countries) != 1 {
+		return nil, usageError("expected a country")
+	}
+	start, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, usageError("start year %q is not a year", args[1])
+	}
+	end, err := strconv.Atoi(args[2])
+	if err != nil {
+		return nil, usageError("end year %q is not a year", args[2])
+	}
+	
+	history, err := c.client.GetHistoricalPPP(ctx, countries[0], start, end)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Year < history[j].Year
+	})
+	
+	res := &result{value: history, header: []string{"country_code", "year", "factor"}}
+	for _, data := range history {
+		res.rows = append(res.rows, []string{data.CountryCode, strconv.Itoa(data.Year), number(data.Factor)})
+	}
+	return res, nil
+}
+
+// runCompare ranks countries by PPP factor
+func runCompare(ctx context.Context, c *cli, args []string) (*result, error) {
+	countries, err := countryArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(countries) < 2 {
+		return nil, usageError("expected at least two countries")
+	}
+	
+	comparison, err := c.client.ComparePPP(ctx, countries)
+	if err != nil {
+		return nil, err
+	}
+	
+	res := &result{value: comparison, header: []string{"rank", "country", "country_name", "factor", "percent_of_us"}}
+	for _, cc := range comparison {
+		res.rows = append(res.rows, []string{strconv.Itoa(cc.Rank), cc.Country, cc.CountryName, number(cc.Factor), fixed(cc.PercentOfUS, 1)})
+	}
+	return res, nil
+}
+
+// runCache exports the cache to a file or imports one into it
+// Both print the entries cached afterwards
+func runCache(ctx context.Context, c *cli, args []string) (*result, error) {
+	if len(args) == 0 {
+		return nil, usageError("expected export or import")
+	}
+	
+	switch args[0] {
+	case "export":
+		fs := commandFlags(c, "cache export")
+		countries := fs.String("countries", "", "preload PPP data for these countries first (comma separated, or all)")
+		currencies := fs.String("currencies", "USD", "base currencies whose rates are preloaded with -countries")
+		if err := parseFlags(fs, args[1:]); err != nil {
+			return nil, err
+		}
+		if fs.NArg() != 1 {
+			return nil, usageError("expected a file name")
+		}
+		
+		var warmErr error
+		if *countries != "" {
+			warmer := ppp.NewWarmer(c.client,
+				ppp.WithWarmCountries(splitList(*countries)...),
+				ppp.WithWarmCurrencies(splitList(*currencies)...),
+			)
+			warmErr = warmer.Warm(ctx)
+		}
+		if err := c.client.ExportCache(fs.Arg(0)); err != nil {
+			return nil, err
+		}
+		return c.cacheEntries(warmErr)
+	case "import":
+		if len(args) != 2 {
+			return nil, usageError("expected a file name")
+		}
+		if err := c.client.ImportCache(args[1]); err != nil {
+			return nil, err
+		}
+		return c.cacheEntries(nil)
+	default:
+		return nil, usageError("unknown cache command %q", args[0])
+	}
+}
+
+// cacheEntries lists the cached entries, returning err alongside them
+func (c *cli) cacheEntries(err error) (*result, error) {
+	entries, entriesErr := c.client.CacheEntries()
+	if entriesErr != nil {
+		return nil, errors.Join(err, entriesErr)
+	}
+	
+	res := &result{value: entries, header: []string{"key", "family", "expires_at"}}
+	for _, entry := range entries {
+		expires := ""
+		if !entry.ExpiresAt.IsZero() {
+			expires = entry.ExpiresAt.Format(time.RFC3339)
+		}
+		res.rows = append(res.rows, []string{entry.Key, entry.Family, expires})
+	}
+	return res, err
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
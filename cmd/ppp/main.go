@@ -0,0 +1,240 @@
+// Command ppp looks up PPP factors, exchange rates and PPP-adjusted prices
+// from the command line
+//
+// Usage:
+//
+//	ppp [flags] <command> [arguments]
+//
+// Commands:
+//
+//	recommend <price> <currency> <country>
+//	batch <price> <currency> <country>...
+//	factor <country>...
+//	rate <from> <to>...
+//	countries
+//	search-indicators <query>
+//	history <country> <start-year> <end-year>
+//	compare <country> <country>...
+//	cache export <file>
+//	cache import <file>
+//
+// Results are printed as a table, JSON (-o json) or CSV (-o csv). With
+// -cache-file the cache is loaded before and saved after each command, and
+// -offline answers from that file and the embedded data without network access
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// cli holds the state shared by all commands
+type cli struct {
+	client *ppp.Client
+	stdout io.Writer
+	stderr io.Writer
+	format string
+}
+
+// command is a ppp subcommand
+type command struct {
+	name string
+	args string
+	help string
+	run  func(ctx context.Context, c *cli, args []string) (*result, error)
+}
+
+// commands lists the subcommands in the order shown by -h
+var commands = []command{
+	{"recommend", "[-as-of date] <price> <currency> <country>", "PPP-adjusted price for a country", runRecommend},
+	{"batch", "[-as-of date] <price> <currency> <country>...", "PPP-adjusted prices for several countries", runBatch},
+	{"factor", "<country>...", "latest PPP conversion factors", runFactor},
+	{"rate", "<from> <to>...", "exchange rates from one currency", runRate},
+	{"countries", "[-region name] [-income level]", "countries with World Bank data", runCountries},
+	{"search-indicators", "<query>", "search World Bank indicators", runSearchIndicators},
+	{"history", "<country> <start-year> <end-year>", "PPP factors by year", runHistory},
+	{"compare", "<country> <country>...", "rank countries by PPP factor", runCompare},
+	{"cache", "export|import [flags] <file>", "save or load cached data", runCache},
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ppp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		format        = fs.String("o", "table", "output format: table, json or csv")
+		cacheTTL      = fs.Duration("cache", 24*time.Hour, "cache duration, 0 to disable caching")
+		cacheFile     = fs.String("cache-file", "", "load the cache from this file and save it after each command")
+		offline       = fs.Bool("offline", false, "never call the upstream APIs; use cached and embedded data only")
+		liveCountries = fs.Bool("live-countries", false, "fetch the country list from the World Bank instead of the embedded snapshot")
+		timeout       = fs.Duration("timeout", 30*time.Second, "timeout for World Bank and currency API calls")
+		worldBankURL  = fs.String("worldbank-url", "", "World Bank API base URL")
+		currencyURL   = fs.String("currency-url", "", "currency API base URL")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ppp [flags] <command> [arguments]\n\nCommands:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(stderr, "  %-18s %s\n  %-18s   %s\n", cmd.name, cmd.help, "", cmd.args)
+		}
+		fmt.Fprintf(stderr, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	cmd, ok := lookupCommand(fs.Arg(0))
+	if !ok {
+		fmt.Fprintf(stderr, "ppp: unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+	if !validFormat(*format) {
+		fmt.Fprintf(stderr, "ppp: unknown output format %q\n", *format)
+		return exitUsage
+	}
+	
+	opts := []ppp.Option{ppp.WithTimeout(*timeout)}
+	if *cacheTTL > 0 {
+		opts = append(opts, ppp.WithCache(*cacheTTL))
+	} else if *cacheFile != "" {
+		fmt.Fprintln(stderr, "ppp: -cache-file requires caching")
+		return exitUsage
+	} else {
+		opts = append(opts, ppp.WithoutCache())
+	}
+	if *offline {
+		opts = append(opts, ppp.WithOffline())
+	}
+	if *liveCountries {
+		opts = append(opts, ppp.WithLiveCountries())
+	}
+	if *worldBankURL != "" {
+		opts = append(opts, ppp.WithWorldBankURL(*worldBankURL))
+	}
+	if *currencyURL != "" {
+		opts = append(opts, ppp.WithCurrencyURL(*currencyURL))
+	}
+	
+	c := &cli{
+		client: ppp.NewClient(opts...),
+		stdout: stdout,
+		stderr: stderr,
+		format: *format,
+	}
+	
+	if *cacheFile != "" {
+		if err := c.client.ImportCache(*cacheFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(stderr, "ppp: %v\n", err)
+			return exitError
+		}
+	}
+	
+	res, err := cmd.run(ctx, c, fs.Args()[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(stderr, "ppp: %v\nUsage: ppp %s %s\n", err, cmd.name, cmd.args)
+		return exitUsage
+	}
+	if res != nil {
+		if werr := res.write(stdout, c.format); werr != nil {
+			err = errors.Join(err, werr)
+		}
+	}
+	
+	// Keep whatever was fetched, even when some lookups failed
+	if *cacheFile != "" {
+		if serr := c.client.ExportCache(*cacheFile); serr != nil {
+			err = errors.Join(err, serr)
+		}
+	}
+	
+	if err != nil {
+		fmt.Fprintf(stderr, "ppp: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// lookupCommand finds a subcommand by name
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// errUsage marks invalid command arguments
+var errUsage = errors.New("invalid arguments")
+
+// usageError returns an error wrapping errUsage
+func usageError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// commandFlags returns a flag set for a subcommand that reports errors to stderr
+func commandFlags(c *cli, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseFlags parses subcommand flags, turning failures into usage errors
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+	return nil
+}
+
+// countryArgs normalizes country codes, keeping their order and dropping duplicates
+func countryArgs(args []string) ([]string, error) {
+	seen := make(map[string]bool, len(args))
+	codes := make([]string, 0, len(args))
+	for _, arg := range args {
+		for _, item := range strings.Split(arg, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			code, err := ppp.NormalizeCountryCode(item)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+// runCLI runs the command line against fake upstreams
+func runCLI(t *testing.T, u *ppptest.Upstream, args ...string) (int, string, string) {
+	t.Helper()
+	if u != nil {
+		args = append([]string{"-worldbank-url", u.WorldBank.URL, "-currency-url", u.Currency.URL}, args...)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRecommend(t *testing.T) {
+	u := ppptest.NewUpstream(t, nil)
+
+	code, out, errOut := runCLI(t, u, "recommend", "100", "usd", "TUR")
+	if code != exitOK {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "COUNTRY") || !strings.Contains(lines[1], "1155") {
+		t.Errorf("Unexpected table:\n%s", out)
+	}
+
+	code, out, errOut = runCLI(t, u, "-o", "json", "recommend", "100", "USD", "TR")
+	if code != exitOK {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	var rec recommendation
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if rec.Country != "TR" || rec.TargetCurrency != "TRY" || rec.RecommendedPrice != 1155 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	u := ppptest.NewUpstream(t, nil)
+
+	code, out, errOut := runCLI(t, u, "-o", "csv", "batch", "100", "USD", "TR,IN", "tr", "AFG")
+	if code != exitError {
+		t.Errorf("Expected exit %d for a country without data, got %d", exitError, code)
+	}
+	if !strings.Contains(errOut, "AF:") {
+		t.Errorf("Expected the failed country on stderr, got %q", errOut)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "country" || rows[1][0] != "TR" || rows[2][0] != "IN" {
+		t.Errorf("Unexpected CSV %v", rows)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	u := ppptest.NewUpstream(t, nil)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"factor", "TR", "DE"}, "11.55"},
+		{[]string{"rate", "USD", "TRY", "eur"}, "40.47"},
+		{[]string{"countries", "-region", "Europe & Central Asia"}, "Turkiye"},
+		{[]string{"history", "TR", "2020", "2022"}, "2021"},
+		{[]string{"compare", "TR", "DE", "US"}, "PERCENT_OF_US"},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runCLI(t, u, tt.args...)
+		if code != exitOK || !strings.Contains(out, tt.want) {
+			t.Errorf("%v = %d %q %q, want output containing %q", tt.args, code, out, errOut, tt.want)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"unknown"},
+		{"-o", "yaml", "factor", "TR"},
+		{"recommend", "100", "USD"},
+		{"recommend", "100", "USD", "TR", "IN"},
+		{"recommend", "100", "USD", ","},
+		{"recommend", "100", "USD", "TR,DE"},
+		{"batch", "100", "USD", " , "},
+		{"history", "", "2015", "2020"},
+		{"history", "TR,DE", "2015", "2020"},
+		{"compare", "TR"},
+		{"history", "TR", "x", "2022"},
+		{"cache", "list"},
+	}
+	for _, args := range tests {
+		if code, _, _ := runCLI(t, nil, args...); code != exitUsage {
+			t.Errorf("%v = %d, want %d", args, code, exitUsage)
+		}
+	}
+
+	if code, _, errOut := runCLI(t, nil, "recommend", "100", "USD", "ZZ"); code != exitError || !strings.Contains(errOut, "INVALID_INPUT") {
+		t.Errorf("Expected INVALID_INPUT for an unknown country, got %d %q", code, errOut)
+	}
+}
+
+func TestCacheFile(t *testing.T) {
+	u := ppptest.NewUpstream(t, nil)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cache.json")
+
+	code, out, errOut := runCLI(t, u, "cache", "export", "-countries", "TR,DE", file)
+	if code != exitOK || !strings.Contains(out, "ppp:TR") || !strings.Contains(out, "rate:USD:TRY") {
+		t.Fatalf("export = %d %q %q", code, out, errOut)
+	}
+
+	// Offline runs are answered from the exported file alone
+	requests := u.Requests()
+	code, out, errOut = runCLI(t, nil, "-offline", "-cache-file", file, "recommend", "100", "USD", "TR")
+	if code != exitOK || !strings.Contains(out, "1155") {
+		t.Errorf("offline recommend = %d %q %q", code, out, errOut)
+	}
+	code, _, errOut = runCLI(t, nil, "-offline", "-cache-file", file, "factor", "IN")
+	if code != exitError || !strings.Contains(errOut, "offline") {
+		t.Errorf("Expected an offline error for uncached data, got %d %q", code, errOut)
+	}
+	if got := u.Requests(); got != requests {
+		t.Errorf("Expected no upstream requests offline, got %d", got-requests)
+	}
+
+	copied := filepath.Join(dir, "copy.json")
+	code, out, errOut = runCLI(t, nil, "-offline", "-cache-file", copied, "-o", "json", "cache", "import", file)
+	if code != exitOK {
+		t.Fatalf("import = %d %q", code, errOut)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil || len(entries) == 0 {
+		t.Errorf("Expected imported entries, got %v %q", err, out)
+	}
+	if code, _, errOut = runCLI(t, nil, "-offline", "-cache-file", copied, "factor", "DE"); code != exitOK {
+		t.Errorf("Expected the imported file to be saved to -cache-file, got %d %q", code, errOut)
+	}
+}
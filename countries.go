@@ -0,0 +1,155 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// iso3166Data is the embedded ISO 3166-1 registry
+//go:embed data/iso3166.json
+var iso3166Data []byte
+
+// ISOCountry describes an ISO 3166-1 country
+type ISOCountry struct {
+	Alpha2  string `json:"alpha2"`  // two-letter code, e.g. "TR"
+	Alpha3  string `json:"alpha3"`  // three-letter code, e.g. "TUR"
+	Numeric int    `json:"numeric"` // numeric code, e.g. 792; 0 for user-assigned codes such as XK
+	Name    string `json:"name"`    // English short name
+}
+
+// countryRegistry indexes the embedded countries
+type countryRegistry struct {
+	countries []ISOCountry
+	byAlpha2  map[string]*ISOCountry
+	byAlpha3  map[string]*ISOCountry
+	byNumeric map[int]*ISOCountry
+	aliases   map[string]string
+}
+
+var (
+	countriesOnce sync.Once
+	countries     *countryRegistry
+)
+
+// loadCountries parses the embedded registry once
+func loadCountries() *countryRegistry {
+	countriesOnce.Do(func() {
+		var data struct {
+			Aliases   map[string]string `json:"aliases"`
+			Countries []ISOCountry      `json:"countries"`
+		}
+		if err := json.Unmarshal(iso3166Data, &data); err != nil {
+			panic("ppp: invalid embedded ISO 3166 data: " + err.Error())
+		}
+		
+		registry := &countryRegistry{
+			countries: data.Countries,
+			byAlpha2:  make(map[string]*ISOCountry, len(data.Countries)),
+			byAlpha3:  make(map[string]*ISOCountry, len(data.Countries)),
+			byNumeric: make(map[int]*ISOCountry, len(data.Countries)),
+			aliases:   data.Aliases,
+		}
+		
+		for i := range registry.countries {
+			c := &registry.countries[i]
+			registry.byAlpha2[c.Alpha2] = c
+			registry.byAlpha3[c.Alpha3] = c
+			if c.Numeric != 0 {
+				registry.byNumeric[c.Numeric] = c
+			}
+		}
+		
+		countries = registry
+	})
+	return countries
+}
+
+// find looks up a code in any supported form
+func (r *countryRegistry) find(code string) (*ISOCountry, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	
+	switch {
+	case len(code) == 2:
+		if alias, ok := r.aliases[code]; ok {
+			code = alias
+		}
+		c, ok := r.byAlpha2[code]
+		return c, ok
+	case len(code) == 3 && isDigits(code):
+		numeric, _ := strconv.Atoi(code)
+		c, ok := r.byNumeric[numeric]
+		return c, ok
+	case len(code) == 3:
+		c, ok := r.byAlpha3[code]
+		return c, ok
+	}
+	return nil, false
+}
+
+// LookupCountry returns the ISO 3166-1 entry for a country code
+// Accepts alpha-2, alpha-3 and three-digit numeric codes in any case, and the
+// aliases "UK" and "EL"
+// Example: LookupCountry("tur") and LookupCountry("792") both return Türkiye
+func LookupCountry(code string) (ISOCountry, bool) {
+	c, ok := loadCountries().find(code)
+	if !ok {
+		return ISOCountry{}, false
+	}
+	return *c, true
+}
+
+// ISOCountries returns all countries in the registry, sorted by alpha-2 code
+func ISOCountries() []ISOCountry {
+	list := append([]ISOCountry(nil), loadCountries().countries...)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Alpha2 < list[j].Alpha2
+	})
+	return list
+}
+
+// NormalizeCountryCode converts any supported country code to ISO alpha-2
+// Example: NormalizeCountryCode("uk") returns "GB"
+func NormalizeCountryCode(code string) (string, error) {
+	c, ok := loadCountries().find(code)
+	if !ok {
+		return "", NewPPPError(
+			ErrCodeInvalidInput,
+			"unknown ISO 3166-1 country code",
+			ErrInvalidCountry,
+		).WithContext("country_code", code)
+	}
+	return c.Alpha2, nil
+}
+
+// CountryAlpha3 returns the alpha-3 code for a country
+// Example: CountryAlpha3("TR") returns "TUR"
+func CountryAlpha3(code string) (string, bool) {
+	c, ok := loadCountries().find(code)
+	if !ok {
+		return "", false
+	}
+	return c.Alpha3, true
+}
+
+// CountryNumeric returns the numeric code for a country
+// Example: CountryNumeric("TR") returns 792
+func CountryNumeric(code string) (int, bool) {
+	c, ok := loadCountries().find(code)
+	if !ok || c.Numeric == 0 {
+		return 0, false
+	}
+	return c.Numeric, true
+}
+
+// canonicalCountry normalizes a country code, passing unknown codes through
+// unchanged so World Bank aggregates such as "EU" or "1W" still work
+func canonicalCountry(code string) string {
+	if normalized, err := NormalizeCountryCode(code); err == nil {
+		return normalized
+	}
+	return code
+}
@@ -0,0 +1,57 @@
+package ppp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+func TestCountryCodeNormalization(t *testing.T) {
+	client := ppptest.NewClient(t)
+	ctx := context.Background()
+
+	for _, code := range []string{"tr", "TUR", "792"} {
+		rec, err := client.Recommend(ctx, 100, "USD", code)
+		if err != nil {
+			t.Fatalf("Recommend(%q) failed: %v", code, err)
+		}
+		if rec.TargetCurrency != "TRY" || rec.PPPFactor != 11.55 {
+			t.Errorf("Recommend(%q) = %+v, want Turkish PPP", code, rec)
+		}
+	}
+
+	data, err := client.GetPPP(ctx, "bra")
+	if err != nil {
+		t.Fatalf("GetPPP failed: %v", err)
+	}
+	if data.CountryCode != "BR" {
+		t.Errorf("CountryCode = %q, want BR", data.CountryCode)
+	}
+
+	for _, code := range []string{"TUR", "792"} {
+		history, err := client.GetHistoricalPPP(ctx, code, 2020, 2022)
+		if err != nil || len(history) != 3 {
+			t.Errorf("GetHistoricalPPP(%q) = %v, %v, want 3 years", code, history, err)
+		}
+		trend, err := client.AnalyzePPPTrend(ctx, code, 2020, 2022)
+		if err != nil || trend.Country != "TR" {
+			t.Errorf("AnalyzePPPTrend(%q) = %+v, %v, want TR", code, trend, err)
+		}
+	}
+	comparisons, err := client.ComparePPP(ctx, []string{"TUR", "076", "de"})
+	if err != nil || len(comparisons) != 3 {
+		t.Fatalf("ComparePPP = %+v, %v, want 3 countries", comparisons, err)
+	}
+	for _, c := range comparisons {
+		if c.Country != "TR" && c.Country != "BR" && c.Country != "DE" {
+			t.Errorf("Expected alpha-2 codes, got %q", c.Country)
+		}
+	}
+
+	if _, err := ppp.NewRecommendationEngine(client).RecommendWithStrategy(ctx, 100, "USD", "ZZ"); !errors.Is(err, ppp.ErrInvalidCountry) {
+		t.Errorf("Expected ErrInvalidCountry for ZZ, got %v", err)
+	}
+}
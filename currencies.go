@@ -0,0 +1,146 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// iso4217Data is the embedded ISO 4217 registry
+//go:embed data/iso4217.json
+var iso4217Data []byte
+
+// Currency describes an ISO 4217 currency
+type Currency struct {
+	Code         string   `json:"code"`          // alphabetic code, e.g. "TRY"
+	Numeric      int      `json:"numeric"`       // numeric code, e.g. 949
+	Name         string   `json:"name"`          // English name
+	Digits       int      `json:"digits"`        // minor unit digits, e.g. 2 for cents
+	CashDigits   int      `json:"cash_digits"`   // digits used for consumer prices, e.g. 0 for IDR
+	Symbol       string   `json:"symbol"`        // unambiguous symbol, e.g. "C$"
+	NarrowSymbol string   `json:"narrow_symbol"` // local symbol, e.g. "$"
+	Countries    []string `json:"countries"`     // ISO 3166-1 alpha-2 codes using the currency
+}
+
+// currencyRegistry indexes the embedded currencies
+type currencyRegistry struct {
+	currencies []Currency
+	byCode     map[string]*Currency
+	byNumeric  map[int]*Currency
+	byCountry  map[string][]*Currency
+}
+
+var (
+	currenciesOnce sync.Once
+	currencies     *currencyRegistry
+)
+
+// loadCurrencies parses the embedded registry once
+func loadCurrencies() *currencyRegistry {
+	currenciesOnce.Do(func() {
+		var entries []struct {
+			Currency
+			CashDigits *int `json:"cash_digits"`
+		}
+		if err := json.Unmarshal(iso4217Data, &entries); err != nil {
+			panic("ppp: invalid embedded ISO 4217 data: " + err.Error())
+		}
+		
+		registry := &currencyRegistry{
+			currencies: make([]Currency, len(entries)),
+			byCode:     make(map[string]*Currency, len(entries)),
+			byNumeric:  make(map[int]*Currency, len(entries)),
+			byCountry:  make(map[string][]*Currency),
+		}
+		
+		for i, entry := range entries {
+			currency := entry.Currency
+			// Cash digits default to the ISO minor unit
+			currency.CashDigits = currency.Digits
+			if entry.CashDigits != nil {
+				currency.CashDigits = *entry.CashDigits
+			}
+			
+			registry.currencies[i] = currency
+			c := &registry.currencies[i]
+			registry.byCode[c.Code] = c
+			registry.byNumeric[c.Numeric] = c
+			for _, country := range c.Countries {
+				registry.byCountry[country] = append(registry.byCountry[country], c)
+			}
+		}
+		
+		currencies = registry
+	})
+	return currencies
+}
+
+// LookupCurrency returns the ISO 4217 metadata for a currency code
+// Example: LookupCurrency("kwd") returns Kuwaiti Dinar with 3 digits
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := loadCurrencies().byCode[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, false
+	}
+	return c.clone(), true
+}
+
+// LookupCurrencyNumeric returns the ISO 4217 metadata for a numeric code
+// Example: LookupCurrencyNumeric(949) returns Turkish Lira
+func LookupCurrencyNumeric(numeric int) (Currency, bool) {
+	c, ok := loadCurrencies().byNumeric[numeric]
+	if !ok {
+		return Currency{}, false
+	}
+	return c.clone(), true
+}
+
+// Currencies returns all currencies in the registry, sorted by code
+func Currencies() []Currency {
+	registry := loadCurrencies()
+	list := make([]Currency, len(registry.currencies))
+	for i := range registry.currencies {
+		list[i] = registry.currencies[i].clone()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Code < list[j].Code
+	})
+	return list
+}
+
+// CurrenciesForCountry returns the currencies used in a country
+// Example: CurrenciesForCountry("PA") returns PAB and USD
+func CurrenciesForCountry(countryCode string) []Currency {
+	var list []Currency
+	for _, c := range loadCurrencies().byCountry[strings.ToUpper(countryCode)] {
+		list = append(list, c.clone())
+	}
+	return list
+}
+
+// clone returns a copy that doesn't share the countries slice with the registry
+func (c *Currency) clone() Currency {
+	copied := *c
+	copied.Countries = append([]string(nil), c.Countries...)
+	return copied
+}
+
+// currencyDigits returns the number of minor unit digits of a currency
+// Unknown currencies use 2 digits
+func currencyDigits(code string) int {
+	if c, ok := loadCurrencies().byCode[code]; ok {
+		return c.Digits
+	}
+	return 2
+}
+
+// currencyCashDigits returns the number of decimals used for consumer prices
+// Unknown currencies use 2 digits
+func currencyCashDigits(code string) int {
+	if c, ok := loadCurrencies().byCode[code]; ok {
+		return c.CashDigits
+	}
+	return 2
+}
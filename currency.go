@@ -18,6 +18,7 @@ const (
 type CurrencyClient struct {
 	baseURL string
 	client  *resty.Client
+	clock   Clock
 }
 
 // NewCurrencyClient creates a new currency API client
@@ -28,6 +29,7 @@ func NewCurrencyClient(baseURL string) *CurrencyClient {
 	
 	return &CurrencyClient{
 		baseURL: baseURL,
+		clock:   SystemClock,
 		client: resty.New().
 			SetTimeout(10 * time.Second).
 			SetRetryCount(3).
@@ -37,11 +39,58 @@ func NewCurrencyClient(baseURL string) *CurrencyClient {
 
 // GetExchangeRate fetches the exchange rate between two currencies
 func (c *CurrencyClient) GetExchangeRate(ctx context.Context, from, to string) (*ExchangeRate, error) {
-	from = strings.ToLower(from)
-	to = strings.ToLower(to)
+	table, err := c.GetRateTable(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	
+	rate, ok := table.Rates[strings.ToUpper(to)]
+	if !ok {
+		return nil, NewPPPError(
+			ErrCodeNoData,
+			fmt.Sprintf("no exchange rate found for %s to %s", strings.ToLower(from), strings.ToLower(to)),
+			ErrNoData,
+		)
+	}
 	
-	url := fmt.Sprintf("%s/currencies/%s.json", c.baseURL, from)
+	return &ExchangeRate{
+		From:        table.Base,
+		To:          strings.ToUpper(to),
+		Rate:        rate,
+		LastUpdated: table.LastUpdated,
+	}, nil
+}
+
+// GetRateTable fetches all exchange rates from a base currency in one request
+func (c *CurrencyClient) GetRateTable(ctx context.Context, base string) (*RateTable, error) {
+	base = strings.ToLower(base)
+	baseURL := strings.ReplaceAll(c.baseURL, "{date}", "latest")
+	return c.fetchRateTable(ctx, fmt.Sprintf("%s/currencies/%s.json", baseURL, base), base)
+}
+
+// GetRateTableAt fetches the exchange rates from a base currency published for a date
+// The default API serves dated snapshots by replacing "@latest" in the URL with
+// "@YYYY-MM-DD". Custom URLs may contain a "{date}" placeholder; otherwise the
+// date is sent as a "date" query parameter.
+func (c *CurrencyClient) GetRateTableAt(ctx context.Context, base string, date time.Time) (*RateTable, error) {
+	base = strings.ToLower(base)
+	day := date.Format("2006-01-02")
+	
+	var url string
+	switch {
+	case strings.Contains(c.baseURL, "{date}"):
+		url = fmt.Sprintf("%s/currencies/%s.json", strings.ReplaceAll(c.baseURL, "{date}", day), base)
+	case strings.Contains(c.baseURL, "@latest"):
+		url = fmt.Sprintf("%s/currencies/%s.json", strings.Replace(c.baseURL, "@latest", "@"+day, 1), base)
+	default:
+		url = fmt.Sprintf("%s/currencies/%s.json?date=%s", c.baseURL, base, day)
+	}
 	
+	return c.fetchRateTable(ctx, url, base)
+}
+
+// fetchRateTable fetches and parses a rate table
+func (c *CurrencyClient) fetchRateTable(ctx context.Context, url, base string) (*RateTable, error) {
 	resp, err := c.client.R().
 		SetContext(ctx).
 		Get(url)
@@ -62,33 +111,38 @@ func (c *CurrencyClient) GetExchangeRate(ctx context.Context, from, to string) (
 	// Extract the date
 	dateStr, ok := data["date"].(string)
 	if !ok {
-		dateStr = time.Now().Format("2006-01-02")
+		dateStr = c.clock.Now().Format("2006-01-02")
 	}
 	
 	// Extract rates
-	rates, ok := data[from].(map[string]interface{})
+	rates, ok := data[base].(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("no rates found for currency %s", from)
+		return nil, NewPPPError(
+			ErrCodeNoData,
+			fmt.Sprintf("no rates found for currency %s", base),
+			ErrNoData,
+		)
 	}
 	
-	rate, ok := rates[to].(float64)
-	if !ok {
-		return nil, fmt.Errorf("no exchange rate found for %s to %s", from, to)
+	upperRates := make(map[string]float64, len(rates))
+	for code, value := range rates {
+		if rate, ok := value.(float64); ok {
+			upperRates[strings.ToUpper(code)] = rate
+		}
 	}
 	
 	lastUpdated, _ := time.Parse("2006-01-02", dateStr)
 	
-	return &ExchangeRate{
-		From:        strings.ToUpper(from),
-		To:          strings.ToUpper(to),
-		Rate:        rate,
+	return &RateTable{
+		Base:        strings.ToUpper(base),
+		Rates:       upperRates,
 		LastUpdated: lastUpdated,
 	}, nil
 }
 
 // GetUSDRates fetches all exchange rates from USD
 func (c *CurrencyClient) GetUSDRates(ctx context.Context) (map[string]float64, error) {
-	url := fmt.Sprintf("%s/currencies/usd.json", c.baseURL)
+	url := fmt.Sprintf("%s/currencies/usd.json", strings.ReplaceAll(c.baseURL, "{date}", "latest"))
 	
 	resp, err := c.client.R().
 		SetContext(ctx).
@@ -143,9 +197,22 @@ func (c *CurrencyClient) ConvertAmount(ctx context.Context, amount float64, from
 }
 
 // GetHistoricalRate fetches exchange rate for a specific date
-// Note: This API might not support historical data, so this is a placeholder
+// Snapshots are only available for dates the API has archived
 func (c *CurrencyClient) GetHistoricalRate(ctx context.Context, from, to string, date time.Time) (*ExchangeRate, error) {
-	// For now, just return current rate
-	// In a real implementation, you might use a different API that supports historical data
-	return c.GetExchangeRate(ctx, from, to)
+	table, err := c.GetRateTableAt(ctx, from, date)
+	if err != nil {
+		return nil, err
+	}
+	
+	rate, ok := table.Rates[strings.ToUpper(to)]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate found for %s to %s on %s", strings.ToLower(from), strings.ToLower(to), date.Format("2006-01-02"))
+	}
+	
+	return &ExchangeRate{
+		From:        table.Base,
+		To:          strings.ToUpper(to),
+		Rate:        rate,
+		LastUpdated: table.LastUpdated,
+	}, nil
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package ppp
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -26,6 +27,9 @@ var (
 	ErrAPIUnavailable    = errors.New("API service unavailable")
 	ErrCacheDisabled     = errors.New("cache is disabled")
 	ErrInvalidDateRange  = errors.New("invalid date range")
+	ErrRateLimited       = errors.New("rate limit exceeded")
+	ErrCircuitOpen       = errors.New("circuit breaker open")
+	ErrOffline           = errors.New("offline mode: network access disabled")
 )
 
 // PPPError represents a detailed error with code and context
@@ -92,7 +96,18 @@ func IsNetworkError(err error) bool {
 	return false
 }
 
-// ValidateCountryCode validates country code format
+// IsRateLimitError checks if error is a rate limit error
+func IsRateLimitError(err error) bool {
+	var pppErr *PPPError
+	if errors.As(err, &pppErr) {
+		return pppErr.Code == ErrCodeRateLimit
+	}
+	return errors.Is(err, ErrRateLimited)
+}
+
+// ValidateCountryCode validates a country code against ISO 3166-1 alpha-2
+// Case is ignored and the aliases "UK" and "EL" are accepted; use
+// NormalizeCountryCode to get the canonical code
 func ValidateCountryCode(code string) error {
 	if len(code) != 2 {
 		return NewPPPError(
@@ -102,15 +117,20 @@ func ValidateCountryCode(code string) error {
 		).WithContext("country_code", code)
 	}
 	
-	// Check if it's uppercase
-	for _, char := range code {
-		if char < 'A' || char > 'Z' {
-			return NewPPPError(
-				ErrCodeInvalidInput,
-				"country code must be uppercase letters",
-				ErrInvalidCountry,
-			).WithContext("country_code", code)
-		}
+	if !isAlpha(code) {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			"country code must be letters",
+			ErrInvalidCountry,
+		).WithContext("country_code", code)
+	}
+	
+	if _, ok := LookupCountry(code); !ok {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			"unknown ISO 3166-1 country code",
+			ErrInvalidCountry,
+		).WithContext("country_code", code)
 	}
 	
 	return nil
@@ -137,11 +157,27 @@ func ValidateCurrencyCode(code string) error {
 		}
 	}
 	
+	if _, ok := LookupCurrency(code); !ok {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			"unknown ISO 4217 currency code",
+			ErrInvalidCurrency,
+		).WithContext("currency_code", code)
+	}
+	
 	return nil
 }
 
 // ValidateAmount validates monetary amount
 func ValidateAmount(amount float64) error {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			"amount must be a finite number",
+			ErrInvalidAmount,
+		).WithContext("amount", fmt.Sprint(amount))
+	}
+	
 	if amount < 0 {
 		return NewPPPError(
 			ErrCodeInvalidInput,
@@ -170,10 +206,14 @@ func ValidateAmount(amount float64) error {
 	return nil
 }
 
-// ValidateDateRange validates year range
+// ValidateDateRange validates year range against the default client's clock
+// Use Client.ValidateDateRange for a client with its own clock
 func ValidateDateRange(startYear, endYear int) error {
-	currentYear := getCurrentYear()
-	
+	return validateDateRange(startYear, endYear, getCurrentYear())
+}
+
+// validateDateRange validates year range against the current year
+func validateDateRange(startYear, endYear, currentYear int) error {
 	if startYear < 1960 {
 		return NewPPPError(
 			ErrCodeInvalidInput,
@@ -201,7 +241,10 @@ func ValidateDateRange(startYear, endYear int) error {
 	return nil
 }
 
-// getCurrentYear is a helper to get current year
+// getCurrentYear is a helper to get current year on the default client's clock
 func getCurrentYear() int {
+	if defaultClient != nil {
+		return defaultClient.now().Year()
+	}
 	return time.Now().Year()
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// PaddleHeader is the header of WritePaddle
+var PaddleHeader = []string{"price_id", "country_code", "currency_code", "amount"}
+
+// WritePaddle writes a CSV of Paddle unit price overrides, one row per
+// catalog item and country. Amounts are in the lowest denomination of the
+// currency, as Paddle expects them
+func WritePaddle(w io.Writer, book *ppp.PriceBook, opts ...Option) error {
+	list, err := items(book)
+	if err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	
+	cw := csv.NewWriter(w)
+	if err := cw.Write(PaddleHeader); err != nil {
+		return err
+	}
+	for _, it := range list {
+		id := cfg.itemID(it.product, it.plan)
+		for _, e := range it.entries {
+			if err := cw.Write([]string{id, e.Country, e.Price.Currency, strconv.FormatInt(e.Price.Amount, 10)}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ShopifyHeader is the header of WriteShopify
+var ShopifyHeader = []string{"Variant SKU", "Market", "Country Code", "Currency", "Price", "Compare At Price"}
+
+// WriteShopify writes a Shopify Markets price list CSV with a fixed price
+// per variant and country market. Discounted prices carry the price at the
+// market exchange rate as the compare-at price
+func WriteShopify(w io.Writer, book *ppp.PriceBook, opts ...Option) error {
+	list, err := items(book)
+	if err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ShopifyHeader); err != nil {
+		return err
+	}
+	for _, it := range list {
+		sku := cfg.itemID(it.product, it.plan)
+		for _, e := range it.entries {
+			market := e.Country
+			if country, ok := ppp.LookupCountry(e.Country); ok {
+				market = country.Name
+			}
+			compareAt := ""
+			if cmp, err := e.MarketPrice.Cmp(e.Price); err == nil && cmp > 0 {
+				compareAt = e.MarketPrice.Decimal()
+			}
+			if err := cw.Write([]string{sku, market, e.Country, e.Price.Currency, e.Price.Decimal(), compareAt}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,159 @@
+// Package export writes price books in the import formats of payment
+// platforms and app stores: Stripe prices with currency options, Paddle
+// price overrides, Shopify Markets price lists, App Store Connect price
+// schedules and Google Play in-app product CSVs
+//
+// Every writer takes a ppp.PriceBook, so the prices, rounding and policy
+// limits are decided once by ppp.PriceBookBuilder. App Store and Google Play
+// prices are snapped to the store's price points, see ppp.SnapPrice
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// ErrEmptyPriceBook is returned for price books without entries
+var ErrEmptyPriceBook = errors.New("price book has no entries")
+
+// Export formats accepted by Write
+const (
+	FormatStripe     = "stripe"
+	FormatPaddle     = "paddle"
+	FormatShopify    = "shopify"
+	FormatAppStore   = "appstore"
+	FormatGooglePlay = "googleplay"
+)
+
+// Formats lists the export formats
+var Formats = []string{FormatStripe, FormatPaddle, FormatShopify, FormatAppStore, FormatGooglePlay}
+
+// config holds the export options
+type config struct {
+	ids      map[string]string
+	snapMode ppp.SnapMode
+}
+
+// Option is a functional option for configuring an export
+type Option func(*config)
+
+// WithProductIDs maps catalog items to the IDs used by the platform, such as
+// Stripe product IDs, Paddle price IDs or store product IDs
+// Keys are "product/plan" for a plan or "product" for a whole product;
+// unmapped items are exported as "product_plan"
+// Example: WithProductIDs(map[string]string{"pro/monthly": "pri_01h8", "pro": "prod_Nf3"})
+func WithProductIDs(ids map[string]string) Option {
+	return func(c *config) {
+		c.ids = ids
+	}
+}
+
+// WithSnapMode sets how App Store and Google Play prices snap to the
+// store's price points (default: ppp.SnapNearest)
+func WithSnapMode(mode ppp.SnapMode) Option {
+	return func(c *config) {
+		c.snapMode = mode
+	}
+}
+
+// newConfig applies options
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// itemID returns the platform ID of a catalog item
+func (c *config) itemID(product, plan string) string {
+	if id, ok := c.ids[itemKey(product, plan)]; ok {
+		return id
+	}
+	if plan == "" {
+		return product
+	}
+	return product + "_" + plan
+}
+
+// productID returns the platform ID of a product
+func (c *config) productID(product string) string {
+	if id, ok := c.ids[product]; ok {
+		return id
+	}
+	return product
+}
+
+// storePrice snaps the price of an entry to a store price point
+// Returns a NO_DATA error when the store has no ladder for the currency
+func (c *config) storePrice(store string, e ppp.PriceBookEntry) (ppp.Money, error) {
+	snap, err := ppp.SnapPrice(store, e.Price.Currency, e.Price.Float64(), c.snapMode)
+	if err != nil {
+		var pe *ppp.PPPError
+		if errors.As(err, &pe) {
+			pe.WithContext("country", e.Country)
+		}
+		return ppp.Money{}, err
+	}
+	return snap.Price, nil
+}
+
+// itemKey returns the WithProductIDs key of a catalog item
+func itemKey(product, plan string) string {
+	if plan == "" {
+		return product
+	}
+	return product + "/" + plan
+}
+
+// Write exports a price book in one of the Formats
+func Write(w io.Writer, format string, book *ppp.PriceBook, opts ...Option) error {
+	switch strings.ToLower(format) {
+	case FormatStripe:
+		return WriteStripe(w, book, opts...)
+	case FormatPaddle:
+		return WritePaddle(w, book, opts...)
+	case FormatShopify:
+		return WriteShopify(w, book, opts...)
+	case FormatAppStore:
+		return WriteAppStore(w, book, opts...)
+	case FormatGooglePlay:
+		return WriteGooglePlay(w, book, opts...)
+	default:
+		return ppp.NewPPPError(ppp.ErrCodeInvalidInput, fmt.Sprintf("unknown export format %q", format), nil).
+			WithContext("formats", Formats)
+	}
+}
+
+// item is a catalog item with its localized prices
+type item struct {
+	product string
+	plan    string
+	base    ppp.Money
+	entries []ppp.PriceBookEntry
+}
+
+// items groups price book entries by catalog item, in book order
+func items(book *ppp.PriceBook) ([]*item, error) {
+	if book == nil || len(book.Entries) == 0 {
+		return nil, ErrEmptyPriceBook
+	}
+	
+	var list []*item
+	index := make(map[string]*item)
+	for _, e := range book.Entries {
+		key := itemKey(e.Product, e.Plan)
+		it, ok := index[key]
+		if !ok {
+			it = &item{product: e.Product, plan: e.Plan, base: e.Base}
+			index[key] = it
+			list = append(list, it)
+		}
+		it.entries = append(it.entries, e)
+	}
+	return list, nil
+}
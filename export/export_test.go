@@ -0,0 +1,237 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// entry returns a price book entry for a country
+func entry(product, plan, country string, base, price, market ppp.Money) ppp.PriceBookEntry {
+	return ppp.PriceBookEntry{Product: product, Plan: plan, Country: country, Base: base, Price: price, MarketPrice: market}
+}
+
+// loadPricePoints loads the price point fixture, which stands in for
+// ladders generated from store exports
+func loadPricePoints(t *testing.T) {
+	t.Helper()
+	f, err := os.Open("../testdata/pricepoints.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := ppp.LoadPricePoints(f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testBook() *ppp.PriceBook {
+	monthly := ppp.NewMoney(2900, "USD")
+	lifetime := ppp.NewMoney(19900, "USD")
+	return &ppp.PriceBook{
+		Currency: "USD",
+		Entries: []ppp.PriceBookEntry{
+			entry("pro", "monthly", "DE", monthly, ppp.NewMoney(2499, "EUR"), ppp.NewMoney(2668, "EUR")),
+			entry("pro", "monthly", "FR", monthly, ppp.NewMoney(2699, "EUR"), ppp.NewMoney(2668, "EUR")),
+			entry("pro", "monthly", "KW", monthly, ppp.NewMoney(3125, "KWD"), ppp.NewMoney(8900, "KWD")),
+			entry("pro", "monthly", "TR", monthly, ppp.NewMoney(115499, "TRY"), ppp.NewMoney(117363, "TRY")),
+			entry("pro", "monthly", "US", monthly, monthly, monthly),
+			entry("pro", "lifetime", "JP", lifetime, ppp.NewMoney(19999, "JPY"), ppp.NewMoney(29850, "JPY")),
+			entry("pro", "lifetime", "US", lifetime, lifetime, lifetime),
+		},
+	}
+}
+
+func TestStripe(t *testing.T) {
+	prices, err := StripePrices(testBook(), WithProductIDs(map[string]string{"pro": "prod_123", "pro/lifetime": "pro_forever"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Expected one price per catalog item, got %d", len(prices))
+	}
+
+	monthly := prices[0]
+	if monthly.Product != "prod_123" || monthly.LookupKey != "pro_monthly" || monthly.Currency != "usd" || monthly.UnitAmount != 2900 {
+		t.Errorf("Unexpected price %+v", monthly)
+	}
+	if monthly.Recurring == nil || monthly.Recurring.Interval != "month" {
+		t.Errorf("Expected a monthly recurring price, got %+v", monthly.Recurring)
+	}
+	want := map[string]int64{"eur": 2699, "kwd": 3130, "try": 115499}
+	if len(monthly.CurrencyOptions) != len(want) {
+		t.Errorf("Unexpected currency options %+v", monthly.CurrencyOptions)
+	}
+	for currency, amount := range want {
+		if got := monthly.CurrencyOptions[currency].UnitAmount; got != amount {
+			t.Errorf("%s unit_amount = %d, want %d", currency, got, amount)
+		}
+	}
+
+	lifetime := prices[1]
+	if lifetime.LookupKey != "pro_forever" || lifetime.Recurring != nil || lifetime.CurrencyOptions["jpy"].UnitAmount != 19999 {
+		t.Errorf("Unexpected one-time price %+v", lifetime)
+	}
+
+	// Stripe takes ISK and UGX with two decimals, HUF and TWD in whole units
+	special := ppp.NewMoney(2900, "USD")
+	prices, err = StripePrices(&ppp.PriceBook{Currency: "USD", Entries: []ppp.PriceBookEntry{
+		entry("pro", "", "IS", special, ppp.NewMoney(3990, "ISK"), ppp.NewMoney(3990, "ISK")),
+		entry("pro", "", "UG", special, ppp.NewMoney(55000, "UGX"), ppp.NewMoney(107000, "UGX")),
+		entry("pro", "", "HU", special, ppp.NewMoney(549950, "HUF"), ppp.NewMoney(1050000, "HUF")),
+		entry("pro", "", "TW", special, ppp.NewMoney(49949, "TWD"), ppp.NewMoney(93000, "TWD")),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]int64{"isk": 399000, "ugx": 5500000, "huf": 550000, "twd": 49900}
+	for currency, amount := range want {
+		if got := prices[0].CurrencyOptions[currency].UnitAmount; got != amount {
+			t.Errorf("%s unit_amount = %d, want %d", currency, got, amount)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "Stripe", testBook()); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("Invalid Stripe JSON: %v\n%s", err, buf.String())
+	}
+}
+
+// readCSV parses CSV output
+func readCSV(t *testing.T, data string) [][]string {
+	t.Helper()
+	rows, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestPaddle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePaddle(&buf, testBook(), WithProductIDs(map[string]string{"pro/monthly": "pri_01"})); err != nil {
+		t.Fatal(err)
+	}
+	rows := readCSV(t, buf.String())
+	if len(rows) != 8 || strings.Join(rows[0], ",") != strings.Join(PaddleHeader, ",") {
+		t.Fatalf("Unexpected CSV %v", rows)
+	}
+	if got := strings.Join(rows[4], ","); got != "pri_01,TR,TRY,115499" {
+		t.Errorf("TR row = %s", got)
+	}
+	if got := strings.Join(rows[6], ","); got != "pro_lifetime,JP,JPY,19999" {
+		t.Errorf("JP row = %s", got)
+	}
+}
+
+func TestShopify(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteShopify(&buf, testBook()); err != nil {
+		t.Fatal(err)
+	}
+	rows := readCSV(t, buf.String())
+	if len(rows) != 8 {
+		t.Fatalf("Unexpected CSV %v", rows)
+	}
+	if got := strings.Join(rows[4], ","); got != "pro_monthly,Türkiye,TR,TRY,1154.99,1173.63" {
+		t.Errorf("TR row = %s", got)
+	}
+	if got := rows[2]; got[4] != "26.99" || got[5] != "" {
+		t.Errorf("Expected no compare-at price above the market price, got %v", got)
+	}
+	if got := rows[3]; got[4] != "3.125" {
+		t.Errorf("Expected three decimals for KWD, got %v", got)
+	}
+}
+
+func TestAppStore(t *testing.T) {
+	loadPricePoints(t)
+	schedules, err := AppStoreSchedules(testBook())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedules) != 2 || schedules[0].BaseTerritory != "USA" || len(schedules[0].ManualPrices) != 5 {
+		t.Fatalf("Unexpected schedules %+v", schedules)
+	}
+	// Prices snap to the nearest App Store price point
+	wantPrices := []string{"24.99", "26.99", "3.099", "1159.99", "28.99"}
+	for i, p := range schedules[0].ManualPrices {
+		if p.CustomerPrice != wantPrices[i] {
+			t.Errorf("%s price = %s, want %s", p.Territory, p.CustomerPrice, wantPrices[i])
+		}
+	}
+	if p := schedules[0].ManualPrices[3]; p.Territory != "TUR" || p.Currency != "TRY" {
+		t.Errorf("Unexpected TR price %+v", p)
+	}
+	if p := schedules[1].ManualPrices[0]; p.Territory != "JPN" || p.CustomerPrice != "10000" {
+		t.Errorf("Expected the top JPY price point, got %+v", p)
+	}
+
+	schedules, err = AppStoreSchedules(testBook(), WithSnapMode(ppp.SnapDown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := schedules[0].ManualPrices[3]; p.CustomerPrice != "1149.99" {
+		t.Errorf("Expected TR to snap down to 1149.99, got %s", p.CustomerPrice)
+	}
+
+	book := testBook()
+	book.Entries = append(book.Entries, entry("pro", "monthly", "BR", book.Entries[0].Base, ppp.NewMoney(4990, "BRL"), ppp.NewMoney(14500, "BRL")))
+	if _, err := AppStoreSchedules(book); !ppp.IsNoDataError(err) {
+		t.Errorf("Expected NO_DATA for a currency without price points, got %v", err)
+	}
+}
+
+func TestGooglePlay(t *testing.T) {
+	loadPricePoints(t)
+	var buf bytes.Buffer
+	if err := WriteGooglePlay(&buf, testBook()); err != nil {
+		t.Fatal(err)
+	}
+	rows := readCSV(t, buf.String())
+	if len(rows) != 3 || len(rows[1]) != len(GooglePlayHeader) {
+		t.Fatalf("Unexpected CSV %v", rows)
+	}
+	if rows[1][0] != "pro_monthly" || rows[1][4] != "en_US; pro monthly; pro monthly" {
+		t.Errorf("Unexpected row %v", rows[1])
+	}
+	want := "DE; 24990000; FR; 26990000; KW; 3125000; TR; 1154990000; US; 29000000"
+	if rows[1][6] != want {
+		t.Errorf("Price = %q, want %q", rows[1][6], want)
+	}
+	if rows[2][6] != "JP; 19999000000; US; 199000000" {
+		t.Errorf("Price = %q", rows[2][6])
+	}
+
+	book := testBook()
+	book.Entries[3].Price = ppp.NewMoney(2500000, "TRY")
+	buf.Reset()
+	if err := WriteGooglePlay(&buf, book); err != nil {
+		t.Fatal(err)
+	}
+	if rows := readCSV(t, buf.String()); !strings.Contains(rows[1][6], "TR; 20000000000;") {
+		t.Errorf("Expected TR to snap to the top of the Play price range, got %q", rows[1][6])
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var buf bytes.Buffer
+	for _, format := range Formats {
+		if err := Write(&buf, format, &ppp.PriceBook{}); !errors.Is(err, ErrEmptyPriceBook) {
+			t.Errorf("%s: expected ErrEmptyPriceBook, got %v", format, err)
+		}
+	}
+	if err := Write(&buf, "xml", testBook()); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// AppStoreSchedule is the manual price schedule of an in-app purchase or
+// subscription in App Store Connect. Territories are ISO 3166-1 alpha-3
+// codes, as App Store Connect uses them
+type AppStoreSchedule struct {
+	ProductID     string          `json:"product_id"`
+	BaseTerritory string          `json:"base_territory,omitempty"`
+	ManualPrices  []AppStorePrice `json:"manual_prices"`
+}
+
+// AppStorePrice is the customer price in one territory
+type AppStorePrice struct {
+	Territory     string `json:"territory"`
+	Currency      string `json:"currency"`
+	CustomerPrice string `json:"customer_price"`
+}
+
+// AppStoreSchedules converts a price book to App Store Connect price
+// schedules, one per catalog item, with prices snapped to App Store price
+// points
+// The base territory is the United States for USD catalogs, otherwise the
+// first country priced in the catalog currency
+func AppStoreSchedules(book *ppp.PriceBook, opts ...Option) ([]AppStoreSchedule, error) {
+	list, err := items(book)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(opts)
+	
+	schedules := make([]AppStoreSchedule, 0, len(list))
+	for _, it := range list {
+		schedule := AppStoreSchedule{
+			ProductID:    cfg.itemID(it.product, it.plan),
+			ManualPrices: make([]AppStorePrice, 0, len(it.entries)),
+		}
+		for _, e := range it.entries {
+			territory, ok := ppp.CountryAlpha3(e.Country)
+			if !ok {
+				continue
+			}
+			price, err := cfg.storePrice(ppp.StoreAppStore, e)
+			if err != nil {
+				return nil, err
+			}
+			if e.Price.Currency == it.base.Currency && (schedule.BaseTerritory == "" || e.Country == "US") {
+				schedule.BaseTerritory = territory
+			}
+			schedule.ManualPrices = append(schedule.ManualPrices, AppStorePrice{
+				Territory:     territory,
+				Currency:      price.Currency,
+				CustomerPrice: price.Decimal(),
+			})
+		}
+		schedules = append(schedules, schedule)
+	}
+	
+	return schedules, nil
+}
+
+// WriteAppStore writes AppStoreSchedules as a JSON array
+func WriteAppStore(w io.Writer, book *ppp.PriceBook, opts ...Option) error {
+	schedules, err := AppStoreSchedules(book, opts...)
+	if err != nil {
+		return err
+	}
+	
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schedules)
+}
+
+// GooglePlayHeader is the header of the Play Console in-app product CSV
+var GooglePlayHeader = []string{
+	"Product ID", "Published State", "Purchase Type", "Auto Translate",
+	"Locale; Title; Description", "Auto Fill Prices", "Price", "Pricing Template ID",
+}
+
+// WriteGooglePlay writes a Play Console in-app product import CSV, one row
+// per catalog item. Prices are snapped to Google Play price points and
+// listed per country in micro-units, e.g. "US; 29000000; TR; 1155000000",
+// so Play doesn't auto-convert them
+func WriteGooglePlay(w io.Writer, book *ppp.PriceBook, opts ...Option) error {
+	list, err := items(book)
+	if err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	
+	cw := csv.NewWriter(w)
+	if err := cw.Write(GooglePlayHeader); err != nil {
+		return err
+	}
+	for _, it := range list {
+		title := strings.TrimSpace(it.product + " " + it.plan)
+		prices := make([]string, 0, 2*len(it.entries))
+		for _, e := range it.entries {
+			price, err := cfg.storePrice(ppp.StoreGooglePlay, e)
+			if err != nil {
+				return err
+			}
+			prices = append(prices, e.Country, strconv.FormatInt(micros(price), 10))
+		}
+		row := []string{
+			cfg.itemID(it.product, it.plan),
+			"published",
+			"managed_by_android",
+			"false",
+			"en_US; " + title + "; " + title,
+			"false",
+			strings.Join(prices, "; "),
+			"",
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// micros returns an amount in millionths of a currency unit
+func micros(m ppp.Money) int64 {
+	scale := int64(1)
+	for i := m.Digits(); i < 6; i++ {
+		scale *= 10
+	}
+	return m.Amount * scale
+}
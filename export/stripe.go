@@ -0,0 +1,122 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// StripePrice holds the parameters of a Stripe Price creation request
+type StripePrice struct {
+	Product         string                          `json:"product"`
+	LookupKey       string                          `json:"lookup_key"`
+	Currency        string                          `json:"currency"`
+	UnitAmount      int64                           `json:"unit_amount"`
+	Recurring       *StripeRecurring                `json:"recurring,omitempty"`
+	CurrencyOptions map[string]StripeCurrencyOption `json:"currency_options,omitempty"`
+}
+
+// StripeRecurring is the billing interval of a recurring price
+type StripeRecurring struct {
+	Interval string `json:"interval"`
+}
+
+// StripeCurrencyOption is the price of a Stripe Price in another currency
+type StripeCurrencyOption struct {
+	UnitAmount int64 `json:"unit_amount"`
+}
+
+// planIntervals maps common plan names to Stripe billing intervals
+var planIntervals = map[string]string{
+	"daily":    "day",
+	"day":      "day",
+	"weekly":   "week",
+	"week":     "week",
+	"monthly":  "month",
+	"month":    "month",
+	"annual":   "year",
+	"annually": "year",
+	"yearly":   "year",
+	"year":     "year",
+}
+
+// StripePrices converts a price book to Stripe Price creation parameters,
+// one per catalog item, with a currency option for every local currency
+// Stripe prices by currency, not country: when countries share a currency
+// the highest of their prices is used, so no country is undercut by a
+// poorer neighbour. Plans named monthly, annual and so on become recurring
+func StripePrices(book *ppp.PriceBook, opts ...Option) ([]StripePrice, error) {
+	list, err := items(book)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(opts)
+	
+	prices := make([]StripePrice, 0, len(list))
+	for _, it := range list {
+		price := StripePrice{
+			Product:    cfg.productID(it.product),
+			LookupKey:  cfg.itemID(it.product, it.plan),
+			Currency:   strings.ToLower(it.base.Currency),
+			UnitAmount: stripeAmount(it.base),
+		}
+		if interval, ok := planIntervals[strings.ToLower(it.plan)]; ok {
+			price.Recurring = &StripeRecurring{Interval: interval}
+		}
+		
+		for _, e := range it.entries {
+			if e.Price.Currency == it.base.Currency {
+				continue
+			}
+			if price.CurrencyOptions == nil {
+				price.CurrencyOptions = make(map[string]StripeCurrencyOption)
+			}
+			currency := strings.ToLower(e.Price.Currency)
+			amount := stripeAmount(e.Price)
+			if existing, ok := price.CurrencyOptions[currency]; !ok || amount > existing.UnitAmount {
+				price.CurrencyOptions[currency] = StripeCurrencyOption{UnitAmount: amount}
+			}
+		}
+		prices = append(prices, price)
+	}
+	
+	return prices, nil
+}
+
+// WriteStripe writes StripePrices as a JSON array
+func WriteStripe(w io.Writer, book *ppp.PriceBook, opts ...Option) error {
+	prices, err := StripePrices(book, opts...)
+	if err != nil {
+		return err
+	}
+	
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(prices)
+}
+
+// stripeTwoDecimal lists currencies Stripe takes with two decimals although
+// ISO 4217 gives them none; amounts must be whole units, times 100
+var stripeTwoDecimal = map[string]bool{"ISK": true, "UGX": true}
+
+// stripeWholeUnits lists two-decimal currencies Stripe only pays out in
+// whole units, so amounts must be divisible by 100
+var stripeWholeUnits = map[string]bool{"HUF": true, "TWD": true}
+
+// stripeAmount returns an amount in Stripe's smallest currency unit
+// Stripe requires three-decimal currencies to be charged in multiples of 10
+// See https://docs.stripe.com/currencies#special-cases
+func stripeAmount(m ppp.Money) int64 {
+	switch {
+	case stripeTwoDecimal[m.Currency] && m.Digits() == 0:
+		return m.Amount * 100
+	case stripeWholeUnits[m.Currency] && m.Digits() == 2:
+		return (m.Amount + 50) / 100 * 100
+	case m.Digits() == 3:
+		return (m.Amount + 5) / 10 * 10
+	default:
+		return m.Amount
+	}
+}
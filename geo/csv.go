@@ -0,0 +1,141 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ipRange maps an inclusive address range to a country
+type ipRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// RangeDB resolves countries from IP ranges imported from CSV
+// It is safe for concurrent use
+type RangeDB struct {
+	ranges []ipRange
+}
+
+// OpenCSV imports an IP range CSV file
+func OpenCSV(path string) (*RangeDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCSV(f)
+}
+
+// LoadCSV imports IP ranges from CSV rows of start, end and country code
+// Supports DB-IP lite ("1.0.0.0,1.0.0.255,AU") and IP2Location LITE DB1
+// ("16777216","16777471","AU","Australia") for IPv4 and IPv6. Addresses may be
+// written as IP literals or decimal integers. Extra columns, header rows and
+// rows without a country ("-" or "ZZ") are ignored
+func LoadCSV(r io.Reader) (*RangeDB, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	reader.Comment = '#'
+	
+	db := &RangeDB{}
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+		}
+		if len(row) < 3 {
+			return nil, fmt.Errorf("%w: line %d has %d columns, want at least 3", ErrInvalidDatabase, line, len(row))
+		}
+		
+		start, err := parseRangeAddr(row[0])
+		if err != nil {
+			if line == 1 {
+				// Header row
+				continue
+			}
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDatabase, line, err)
+		}
+		end, err := parseRangeAddr(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDatabase, line, err)
+		}
+		
+		// Unassigned space, which may span address families
+		code := strings.ToUpper(strings.TrimSpace(row[2]))
+		if code == "" || code == "-" || code == "ZZ" {
+			continue
+		}
+		
+		if start.Is4() != end.Is4() || end.Less(start) {
+			return nil, fmt.Errorf("%w: line %d: invalid range %s-%s", ErrInvalidDatabase, line, start, end)
+		}
+		
+		db.ranges = append(db.ranges, ipRange{start: start, end: end, country: code})
+	}
+	
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return db, nil
+}
+
+// Len returns the number of imported ranges
+func (db *RangeDB) Len() int {
+	return len(db.ranges)
+}
+
+// Country implements Resolver
+func (db *RangeDB) Country(_ context.Context, ip netip.Addr) (string, error) {
+	ip = ip.Unmap()
+	
+	// The last range starting at or before ip
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return ip.Less(db.ranges[i].start)
+	}) - 1
+	if i < 0 || db.ranges[i].end.Less(ip) {
+		return "", notFound(ip)
+	}
+	
+	return normalizeCountry(db.ranges[i].country, ip)
+}
+
+// maxIPv4 is the largest IPv4 address as an integer
+var maxIPv4 = big.NewInt(1<<32 - 1)
+
+// parseRangeAddr parses an IP literal or a decimal address
+// Decimals up to 2^32-1 are IPv4; larger ones are IPv6, with IPv4-mapped
+// addresses (as in IP2Location's IPv6 files) converted to IPv4
+func parseRangeAddr(s string) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap(), nil
+	}
+	
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 128 {
+		return netip.Addr{}, fmt.Errorf("invalid address %q", s)
+	}
+	
+	if n.Cmp(maxIPv4) <= 0 {
+		var b [4]byte
+		n.FillBytes(b[:])
+		return netip.AddrFrom4(b), nil
+	}
+	
+	var b [16]byte
+	n.FillBytes(b[:])
+	return netip.AddrFrom16(b).Unmap(), nil
+}
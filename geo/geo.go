@@ -0,0 +1,87 @@
+// Package geo resolves a visitor's country from an IP address using locally
+// supplied databases: MaxMind-format MMDB files (GeoLite2, DB-IP lite) and
+// IP range CSV files (DB-IP, IP2Location LITE). Both IPv4 and IPv6 are
+// supported, and countries are returned as ISO 3166-1 alpha-2 codes that
+// pass ppp.ValidateCountryCode
+package geo
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// Common errors
+var (
+	ErrNotFound        = errors.New("no country for address")
+	ErrInvalidDatabase = errors.New("invalid GeoIP database")
+)
+
+// Resolver resolves the country of an IP address
+// Implementations return an error wrapping ErrNotFound when the address has
+// no country, e.g. private ranges or gaps in the database
+type Resolver interface {
+	Country(ctx context.Context, ip netip.Addr) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface
+type ResolverFunc func(ctx context.Context, ip netip.Addr) (string, error)
+
+// Country implements Resolver
+func (f ResolverFunc) Country(ctx context.Context, ip netip.Addr) (string, error) {
+	return f(ctx, ip)
+}
+
+// Chain returns a resolver that tries each resolver in order until one finds
+// a country. Errors other than ErrNotFound stop the chain
+func Chain(resolvers ...Resolver) Resolver {
+	return ResolverFunc(func(ctx context.Context, ip netip.Addr) (string, error) {
+		for _, r := range resolvers {
+			country, err := r.Country(ctx, ip)
+			if err == nil {
+				return country, nil
+			}
+			if !errors.Is(err, ErrNotFound) {
+				return "", err
+			}
+		}
+		return "", notFound(ip)
+	})
+}
+
+// Lookup parses an IP address, including IPv4-mapped IPv6 and zoned
+// addresses, and resolves its country
+// Example: Lookup(ctx, db, "88.255.1.1") returns "TR"
+func Lookup(ctx context.Context, r Resolver, ip string) (string, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", ppp.NewPPPError(
+			ppp.ErrCodeInvalidInput,
+			"invalid IP address",
+			err,
+		).WithContext("ip", ip)
+	}
+	return r.Country(ctx, addr.WithZone("").Unmap())
+}
+
+// normalizeCountry converts a database country code to ISO alpha-2
+// Codes that are not countries, such as the legacy "EU" and "AP", are not found
+func normalizeCountry(code string, ip netip.Addr) (string, error) {
+	country, err := ppp.NormalizeCountryCode(code)
+	if err != nil {
+		return "", notFound(ip)
+	}
+	return country, nil
+}
+
+// notFound returns the error for an address without a country
+func notFound(ip netip.Addr) error {
+	return ppp.NewPPPError(
+		ppp.ErrCodeNoData,
+		"no country for IP address",
+		ErrNotFound,
+	).WithContext("ip", ip.String())
+}
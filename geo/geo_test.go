@@ -0,0 +1,315 @@
+package geo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/netip"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// mmdbWriter builds small MaxMind DB files for tests
+type mmdbWriter struct {
+	recordSize int
+	nodes      [][2]int // child node index, or -1 for empty, or -(2+data index) for data
+	data       [][]byte
+}
+
+func newMMDBWriter(recordSize int) *mmdbWriter {
+	return &mmdbWriter{recordSize: recordSize, nodes: [][2]int{{-1, -1}}}
+}
+
+// insert maps a prefix to a record; IPv4 prefixes go under ::/96
+func (w *mmdbWriter) insert(prefix string, record map[string]interface{}) {
+	p := netip.MustParsePrefix(prefix)
+	addr := p.Addr()
+	bits := p.Bits()
+	if addr.Is4() {
+		addr = netip.AddrFrom16(addrV4InV6(addr))
+		bits += 96
+	}
+	b := addr.As16()
+
+	w.data = append(w.data, encodeValue(record))
+	dataRef := -(2 + len(w.data) - 1)
+
+	node := 0
+	for i := 0; i < bits; i++ {
+		bit := (b[i/8] >> (7 - uint(i%8))) & 1
+		if i == bits-1 {
+			w.nodes[node][bit] = dataRef
+			return
+		}
+		if w.nodes[node][bit] < 0 {
+			w.nodes = append(w.nodes, [2]int{-1, -1})
+			w.nodes[node][bit] = len(w.nodes) - 1
+		}
+		node = w.nodes[node][bit]
+	}
+}
+
+// addrV4InV6 places an IPv4 address in the last four bytes, as in ::/96
+func addrV4InV6(addr netip.Addr) [16]byte {
+	var b [16]byte
+	v4 := addr.As4()
+	copy(b[12:], v4[:])
+	return b
+}
+
+func (w *mmdbWriter) bytes() []byte {
+	nodeCount := len(w.nodes)
+
+	var section []byte
+	offsets := make([]int, len(w.data))
+	for i, d := range w.data {
+		offsets[i] = len(section)
+		section = append(section, d...)
+	}
+
+	value := func(ref int) uint32 {
+		switch {
+		case ref == -1:
+			return uint32(nodeCount)
+		case ref < -1:
+			return uint32(nodeCount + 16 + offsets[-ref-2])
+		}
+		return uint32(ref)
+	}
+
+	var buf bytes.Buffer
+	for _, n := range w.nodes {
+		left, right := value(n[0]), value(n[1])
+		switch w.recordSize {
+		case 24:
+			buf.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+		case 28:
+			buf.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left),
+				byte(left>>20)&0xF0 | byte(right>>24)&0x0F,
+				byte(right >> 16), byte(right >> 8), byte(right)})
+		case 32:
+			buf.Write([]byte{byte(left >> 24), byte(left >> 16), byte(left >> 8), byte(left),
+				byte(right >> 24), byte(right >> 16), byte(right >> 8), byte(right)})
+		}
+	}
+	buf.Write(make([]byte, 16))
+	buf.Write(section)
+	buf.Write(metadataMarker)
+	buf.Write(encodeValue(map[string]interface{}{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(w.recordSize),
+		"ip_version":                  uint16(6),
+		"database_type":               "Test-Country",
+		"binary_format_major_version": uint16(2),
+		"build_epoch":                 uint64(1700000000),
+	}))
+	return buf.Bytes()
+}
+
+// encodeValue encodes strings, unsigned integers and maps in MMDB format
+func encodeValue(v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(control(typeString, len(v)), v...)
+	case uint16:
+		return encodeUint(typeUint16, uint64(v))
+	case uint32:
+		return encodeUint(typeUint32, uint64(v))
+	case uint64:
+		return encodeUint(typeUint64, v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := control(typeMap, len(v))
+		for _, k := range keys {
+			out = append(out, encodeValue(k)...)
+			out = append(out, encodeValue(v[k])...)
+		}
+		return out
+	}
+	panic("unsupported value")
+}
+
+func encodeUint(kind int, v uint64) []byte {
+	var b []byte
+	for ; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	return append(control(kind, len(b)), b...)
+}
+
+func control(kind, size int) []byte {
+	if size >= 29 {
+		panic("size too large for test encoder")
+	}
+	if kind <= 7 {
+		return []byte{byte(kind<<5 | size)}
+	}
+	return []byte{byte(size), byte(kind - 7)}
+}
+
+func country(code string) map[string]interface{} {
+	return map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": code},
+	}
+}
+
+func TestMMDB(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		w := newMMDBWriter(recordSize)
+		w.insert("88.255.0.0/16", country("TR"))
+		w.insert("8.8.8.0/24", country("US"))
+		w.insert("2a01:358::/32", country("TR"))
+		w.insert("2001:db8::/32", map[string]interface{}{
+			"registered_country": map[string]interface{}{"iso_code": "DE"},
+		})
+		w.insert("192.0.2.0/24", country("EU"))
+
+		db, err := NewMMDB(w.bytes())
+		if err != nil {
+			t.Fatalf("NewMMDB(%d) failed: %v", recordSize, err)
+		}
+		if db.DatabaseType != "Test-Country" || db.BuildEpoch != 1700000000 {
+			t.Errorf("Unexpected metadata %q %d", db.DatabaseType, db.BuildEpoch)
+		}
+
+		ctx := context.Background()
+		tests := map[string]string{
+			"88.255.1.1":        "TR",
+			"::ffff:88.255.1.1": "TR",
+			"8.8.8.8":           "US",
+			"2a01:358:1::1":     "TR",
+			"2001:db8::1":       "DE",
+		}
+		for ip, want := range tests {
+			got, err := Lookup(ctx, db, ip)
+			if err != nil || got != want {
+				t.Errorf("record size %d: Lookup(%s) = %q, %v; want %s", recordSize, ip, got, err, want)
+			}
+		}
+
+		for _, ip := range []string{"10.0.0.1", "8.8.4.4", "2001:db9::1", "192.0.2.1"} {
+			if _, err := Lookup(ctx, db, ip); !errors.Is(err, ErrNotFound) {
+				t.Errorf("record size %d: Lookup(%s) error = %v, want ErrNotFound", recordSize, ip, err)
+			}
+		}
+	}
+}
+
+func TestMMDBInvalid(t *testing.T) {
+	if _, err := NewMMDB([]byte("not a database")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("Expected ErrInvalidDatabase, got %v", err)
+	}
+
+	w := newMMDBWriter(24)
+	w.insert("8.8.8.0/24", country("US"))
+	data := w.bytes()
+	if _, err := NewMMDB(data[len(data)-60:]); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("Expected ErrInvalidDatabase for a truncated tree, got %v", err)
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	const dbip = `1.0.0.0,1.0.0.255,AU
+88.255.0.0,88.255.255.255,TR
+8.8.8.0,8.8.8.255,us
+2a01:358::,2a01:358:ffff:ffff:ffff:ffff:ffff:ffff,TR
+10.0.0.0,10.255.255.255,ZZ
+`
+	const ip2location = `"0","281470681743359","-","-"
+"281470681743360","281470698520575","-","-"
+"281470698520576","281470698520831","AU","Australia"
+"281472174850048","281472174915583","TR","Turkey"
+"55832835939132113699861407101725179904","55832836018360276214125744695269130239","TR","Turkey"
+`
+
+	ctx := context.Background()
+	for name, data := range map[string]string{"dbip": dbip, "ip2location": ip2location} {
+		db, err := LoadCSV(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: LoadCSV failed: %v", name, err)
+		}
+
+		for ip, want := range map[string]string{
+			"1.0.0.1":       "AU",
+			"88.255.10.10":  "TR",
+			"2a01:358:1::1": "TR",
+		} {
+			got, err := Lookup(ctx, db, ip)
+			if err != nil || got != want {
+				t.Errorf("%s: Lookup(%s) = %q, %v; want %s", name, ip, got, err, want)
+			}
+		}
+
+		for _, ip := range []string{"0.0.0.1", "10.1.1.1", "2001:db8::1", "255.255.255.255"} {
+			if _, err := Lookup(ctx, db, ip); !errors.Is(err, ErrNotFound) {
+				t.Errorf("%s: Lookup(%s) error = %v, want ErrNotFound", name, ip, err)
+			}
+		}
+	}
+
+	db, _ := LoadCSV(strings.NewReader(dbip))
+	if got, _ := Lookup(ctx, db, "8.8.8.8"); got != "US" {
+		t.Errorf("Expected lowercase codes to be normalized, got %q", got)
+	}
+	if db.Len() != 4 {
+		t.Errorf("Expected 4 ranges, got %d", db.Len())
+	}
+
+	// IP2Location's IPv4 files use plain decimal IPv4 addresses
+	v4, err := LoadCSV(strings.NewReader(`"16777216","16777471","AU","Australia"` + "\n"))
+	if err != nil {
+		t.Fatalf("LoadCSV failed: %v", err)
+	}
+	if got, _ := Lookup(ctx, v4, "1.0.0.200"); got != "AU" {
+		t.Errorf("Expected AU for a decimal IPv4 range, got %q", got)
+	}
+
+	if _, err := LoadCSV(strings.NewReader("1.0.0.0,1.0.0.255,AU\n2.0.0.0,1.0.0.0,AU\n")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("Expected ErrInvalidDatabase for a reversed range, got %v", err)
+	}
+	if _, err := LoadCSV(strings.NewReader("ip_start,ip_end,country\n1.0.0.0,1.0.0.255,AU\n")); err != nil {
+		t.Errorf("Expected the header row to be skipped, got %v", err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	db, _ := LoadCSV(strings.NewReader("88.255.0.0,88.255.255.255,TR\n"))
+	fallback := ResolverFunc(func(ctx context.Context, ip netip.Addr) (string, error) {
+		return "DE", nil
+	})
+
+	chain := Chain(db, fallback)
+	if got, _ := Lookup(ctx, chain, "88.255.0.1"); got != "TR" {
+		t.Errorf("Expected TR from the first resolver, got %q", got)
+	}
+	if got, _ := Lookup(ctx, chain, "1.1.1.1"); got != "DE" {
+		t.Errorf("Expected DE from the fallback, got %q", got)
+	}
+
+	failing := ResolverFunc(func(ctx context.Context, ip netip.Addr) (string, error) {
+		return "", errors.New("boom")
+	})
+	if _, err := Lookup(ctx, Chain(failing, fallback), "1.1.1.1"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the resolver error to stop the chain, got %v", err)
+	}
+
+	_, err := Lookup(ctx, db, "not-an-ip")
+	var pppErr *ppp.PPPError
+	if !errors.As(err, &pppErr) || pppErr.Code != ppp.ErrCodeInvalidInput {
+		t.Errorf("Expected INVALID_INPUT for a bad address, got %v", err)
+	}
+	if _, err := Lookup(ctx, db, "fe80::1%eth0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected zoned addresses to be looked up, got %v", err)
+	}
+	if code, _ := Lookup(ctx, db, "88.255.0.1"); ppp.ValidateCountryCode(code) != nil {
+		t.Errorf("Expected a valid ISO code, got %q", code)
+	}
+}
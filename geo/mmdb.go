@@ -0,0 +1,395 @@
+package geo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// metadataMarker precedes the metadata section at the end of an MMDB file
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// dataSectionSeparator is the gap between the search tree and the data section
+const dataSectionSeparator = 16
+
+// maxPointerDepth bounds pointer chains in malformed files
+const maxPointerDepth = 32
+
+// MMDB is a MaxMind DB format reader for country databases such as
+// GeoLite2-Country, GeoIP2-Country and DB-IP's lite country MMDB
+// It is safe for concurrent use
+type MMDB struct {
+	tree         []byte
+	section      []byte
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	ipv4Start    uint
+	DatabaseType string // e.g. "GeoLite2-Country"
+	BuildEpoch   uint64 // build time as Unix seconds
+}
+
+// OpenMMDB reads an MMDB file into memory
+func OpenMMDB(path string) (*MMDB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDB(data)
+}
+
+// NewMMDB parses an MMDB database held in memory
+func NewMMDB(data []byte) (*MMDB, error) {
+	i := bytes.LastIndex(data, metadataMarker)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: metadata marker not found", ErrInvalidDatabase)
+	}
+	
+	metaDecoder := decoder{buf: data[i+len(metadataMarker):]}
+	value, _, err := metaDecoder.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %v", ErrInvalidDatabase, err)
+	}
+	meta, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+	
+	db := &MMDB{
+		nodeCount:  uint(toUint(meta["node_count"])),
+		recordSize: uint(toUint(meta["record_size"])),
+		ipVersion:  uint(toUint(meta["ip_version"])),
+		BuildEpoch: toUint(meta["build_epoch"]),
+	}
+	db.DatabaseType, _ = meta["database_type"].(string)
+	
+	switch db.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", ErrInvalidDatabase, db.ipVersion)
+	}
+	
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(i) {
+		return nil, fmt.Errorf("%w: search tree exceeds file size", ErrInvalidDatabase)
+	}
+	db.tree = data[:treeSize]
+	db.section = data[treeSize+dataSectionSeparator : i]
+	
+	// IPv4 addresses live under ::/96 in IPv6 databases
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for bit := 0; bit < 96 && node < db.nodeCount; bit++ {
+			node = db.record(node, 0)
+		}
+		db.ipv4Start = node
+	}
+	
+	return db, nil
+}
+
+// Country implements Resolver
+func (db *MMDB) Country(_ context.Context, ip netip.Addr) (string, error) {
+	record, err := db.Lookup(ip)
+	if err != nil {
+		return "", err
+	}
+	
+	// Anycast and satellite ranges may only have a registered country
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := record[key].(map[string]interface{}); ok {
+			if code, ok := country["iso_code"].(string); ok && code != "" {
+				return normalizeCountry(code, ip)
+			}
+		}
+	}
+	
+	// DB-IP and IP2Location style flat records
+	for _, key := range []string{"country_code", "country_short"} {
+		if code, ok := record[key].(string); ok && code != "" {
+			return normalizeCountry(code, ip)
+		}
+	}
+	
+	return "", notFound(ip)
+}
+
+// Lookup returns the raw data record for an IP address
+func (db *MMDB) Lookup(ip netip.Addr) (map[string]interface{}, error) {
+	ip = ip.Unmap()
+	if !ip.IsValid() {
+		return nil, notFound(ip)
+	}
+	
+	node := uint(0)
+	bits := ip.AsSlice()
+	if ip.Is4() && db.ipVersion == 6 {
+		node = db.ipv4Start
+	} else if ip.Is6() && db.ipVersion == 4 {
+		return nil, notFound(ip)
+	}
+	
+	for i := 0; i < len(bits)*8 && node < db.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-uint(i%8))) & 1
+		node = db.record(node, bit)
+	}
+	
+	if node == db.nodeCount {
+		return nil, notFound(ip)
+	}
+	if node < db.nodeCount {
+		return nil, fmt.Errorf("%w: search tree deeper than address", ErrInvalidDatabase)
+	}
+	
+	offset := node - db.nodeCount - dataSectionSeparator
+	d := decoder{buf: db.section}
+	value, _, err := d.decode(offset, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: data record is not a map", ErrInvalidDatabase)
+	}
+	return record, nil
+}
+
+// record reads the left (0) or right (1) record of a search tree node
+func (db *MMDB) record(node, bit uint) uint {
+	b := db.tree[node*db.recordSize/4:]
+	
+	switch db.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// decoder decodes the MaxMind DB data section format
+type decoder struct {
+	buf []byte
+}
+
+// Data field types
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// decode decodes the value at offset and returns it with the offset after it
+func (d *decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxPointerDepth {
+		return nil, 0, fmt.Errorf("data nested too deeply")
+	}
+	
+	ctrl, err := d.byte(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset++
+	
+	kind := uint(ctrl >> 5)
+	if kind == typePointer {
+		pointer, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	
+	if kind == typeExtended {
+		ext, err := d.byte(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		kind = uint(ext) + 7
+		offset++
+	}
+	
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	switch kind {
+	case typeMap:
+		m := make(map[string]interface{})
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key is not a string")
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		var list []interface{}
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			list = append(list, value)
+			offset = next
+		}
+		return list, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+	
+	b, err := d.bytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := offset + size
+	
+	switch kind {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid integer size %d", size)
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		var v uint32
+		for _, c := range b {
+			v = v<<8 | uint32(c)
+		}
+		return int64(int32(v)), next, nil
+	case typeUint128:
+		// Only needed for metadata we don't read; keep the raw bytes
+		return append([]byte(nil), b...), next, nil
+	}
+	
+	return nil, 0, fmt.Errorf("unsupported data type %d", kind)
+}
+
+// pointer decodes a pointer and returns its target and the offset after it
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl>>3) & 0x3
+	b, err := d.bytes(offset, size+1)
+	if err != nil {
+		return 0, 0, err
+	}
+	
+	prefix := uint(ctrl & 0x7)
+	var target uint
+	switch size {
+	case 0:
+		target = prefix<<8 | uint(b[0])
+	case 1:
+		target = (prefix<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 2:
+		target = (prefix<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		target = uint(binary.BigEndian.Uint32(b))
+	}
+	return target, offset + size + 1, nil
+}
+
+// size decodes the payload size from the control byte and following bytes
+func (d *decoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1F)
+	if size < 29 {
+		return size, offset, nil
+	}
+	
+	n := size - 28
+	b, err := d.bytes(offset, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	var v uint
+	for _, c := range b {
+		v = v<<8 | uint(c)
+	}
+	
+	switch size {
+	case 29:
+		return 29 + v, offset + n, nil
+	case 30:
+		return 285 + v, offset + n, nil
+	default:
+		return 65821 + v, offset + n, nil
+	}
+}
+
+// byte returns the byte at offset
+func (d *decoder) byte(offset uint) (byte, error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, fmt.Errorf("unexpected end of data at offset %d", offset)
+	}
+	return d.buf[offset], nil
+}
+
+// bytes returns n bytes at offset
+func (d *decoder) bytes(offset, n uint) ([]byte, error) {
+	if offset+n > uint(len(d.buf)) || offset+n < offset {
+		return nil, fmt.Errorf("unexpected end of data at offset %d", offset)
+	}
+	return d.buf[offset : offset+n], nil
+}
+
+// toUint converts a decoded unsigned integer to uint64
+func toUint(v interface{}) uint64 {
+	n, _ := v.(uint64)
+	return n
+}
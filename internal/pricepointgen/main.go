@@ -0,0 +1,308 @@
+// Command pricepointgen generates data/pricepoints.json from store exports
+//
+// App Store price points come from App Store Connect API responses of
+// GET /v1/inAppPurchasesV2/{id}/pricePoints?include=territory (or
+// /v1/apps/{id}/appPricePoints, /v1/subscriptions/{id}/pricePoints), saved
+// as JSON files, one per page. Every point of a territory is read as
+// attributes.customerPrice in the currency of the included territory.
+// Territories sharing a currency keep only the points all of them accept.
+//
+// Google Play has no fixed ladder: any price inside the range of a currency
+// is accepted, in its minor unit. Ranges come from a CSV with a header of
+// currency,min_price,max_price, exported from the Play Console price ranges.
+//
+// Usage:
+//
+//	go run ./internal/pricepointgen -o data/pricepoints.json \
+//	    -appstore 'exports/appstore/*.json' -appstore-version 2025-06 \
+//	    -googleplay exports/googleplay.csv -googleplay-version 2025-06 \
+//	    -retrieved 2025-06-12
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// Sources recorded next to the version of each store
+const (
+	appStoreSource   = "App Store Connect API price points export"
+	googlePlaySource = "Play Console price ranges export"
+)
+
+// storeData is the generated data of one store, as read by ppp
+type storeData struct {
+	Version    string                 `json:"version"`
+	Source     string                 `json:"source"`
+	Retrieved  string                 `json:"retrieved"`
+	Currencies map[string][][3]string `json:"currencies"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "pricepointgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses flags, reads the exports and writes the data
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("pricepointgen", flag.ContinueOnError)
+	out := fs.String("o", "", "output file (default stdout)")
+	appStore := fs.String("appstore", "", "comma-separated App Store Connect price point JSON files or globs")
+	appStoreVersion := fs.String("appstore-version", "", "App Store price point version, e.g. 2025-06")
+	googlePlay := fs.String("googleplay", "", "Play Console price range CSV")
+	googlePlayVersion := fs.String("googleplay-version", "", "Play Console price range version, e.g. 2025-06")
+	retrieved := fs.String("retrieved", "", "date the exports were taken, YYYY-MM-DD")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *retrieved == "" {
+		return errors.New("-retrieved is required")
+	}
+	if *appStore == "" && *googlePlay == "" {
+		return errors.New("expected -appstore or -googleplay exports")
+	}
+	
+	data := make(map[string]*storeData)
+	if *appStore != "" {
+		if *appStoreVersion == "" {
+			return errors.New("-appstore-version is required")
+		}
+		files, err := expandFiles(*appStore)
+		if err != nil {
+			return err
+		}
+		ladders, err := readAppStore(files)
+		if err != nil {
+			return err
+		}
+		data[ppp.StoreAppStore] = newStoreData(*appStoreVersion, appStoreSource, *retrieved, ladders)
+	}
+	if *googlePlay != "" {
+		if *googlePlayVersion == "" {
+			return errors.New("-googleplay-version is required")
+		}
+		f, err := os.Open(*googlePlay)
+		if err != nil {
+			return err
+		}
+		ranges, err := readGooglePlay(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *googlePlay, err)
+		}
+		data[ppp.StoreGooglePlay] = &storeData{
+			Version:    *googlePlayVersion,
+			Source:     googlePlaySource,
+			Retrieved:  *retrieved,
+			Currencies: ranges,
+		}
+	}
+	
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+	if *out == "" {
+		_, err = stdout.Write(encoded)
+		return err
+	}
+	return os.WriteFile(*out, encoded, 0o644)
+}
+
+// expandFiles expands a comma-separated list of files and globs
+func expandFiles(list string) ([]string, error) {
+	var files []string
+	for _, pattern := range strings.Split(list, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// pricePointsPage is one page of an App Store Connect price points response
+type pricePointsPage struct {
+	Data []struct {
+		Attributes struct {
+			CustomerPrice string `json:"customerPrice"`
+		} `json:"attributes"`
+		Relationships struct {
+			Territory struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			} `json:"territory"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Included []struct {
+		Type       string `json:"type"`
+		ID         string `json:"id"`
+		Attributes struct {
+			Currency string `json:"currency"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+// readAppStore reads App Store Connect price point pages and returns the
+// points, in minor units, every territory of a currency accepts
+func readAppStore(files []string) (map[string][]int64, error) {
+	currencies := make(map[string]string) // territory -> currency
+	prices := make(map[string][]string)   // territory -> customer prices
+	
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var page pricePointsPage
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, inc := range page.Included {
+			if inc.Type == "territories" && inc.Attributes.Currency != "" {
+				currencies[inc.ID] = strings.ToUpper(inc.Attributes.Currency)
+			}
+		}
+		for _, point := range page.Data {
+			territory := point.Relationships.Territory.Data.ID
+			if territory == "" || point.Attributes.CustomerPrice == "" {
+				return nil, fmt.Errorf("%s: price point without territory or customer price", file)
+			}
+			prices[territory] = append(prices[territory], point.Attributes.CustomerPrice)
+		}
+	}
+	
+	// Intersect the points of territories sharing a currency
+	accepted := make(map[string]map[int64]bool)
+	for territory, list := range prices {
+		currency, ok := currencies[territory]
+		if !ok {
+			return nil, fmt.Errorf("territory %s has no currency; export with include=territory", territory)
+		}
+		points := make(map[int64]bool, len(list))
+		for _, price := range list {
+			m, err := ppp.ParseMoney(price, currency)
+			if err != nil {
+				return nil, fmt.Errorf("territory %s: %w", territory, err)
+			}
+			if m.Amount > 0 {
+				points[m.Amount] = true
+			}
+		}
+		
+		if existing, ok := accepted[currency]; ok {
+			for amount := range existing {
+				if !points[amount] {
+					delete(existing, amount)
+				}
+			}
+			continue
+		}
+		accepted[currency] = points
+	}
+	
+	ladders := make(map[string][]int64, len(accepted))
+	for currency, points := range accepted {
+		if len(points) == 0 {
+			continue
+		}
+		amounts := make([]int64, 0, len(points))
+		for amount := range points {
+			amounts = append(amounts, amount)
+		}
+		sort.Slice(amounts, func(i, j int) bool { return amounts[i] < amounts[j] })
+		ladders[currency] = amounts
+	}
+	return ladders, nil
+}
+
+// newStoreData compresses ladders into segments
+func newStoreData(version, source, retrieved string, ladders map[string][]int64) *storeData {
+	d := &storeData{Version: version, Source: source, Retrieved: retrieved, Currencies: make(map[string][][3]string, len(ladders))}
+	for currency, amounts := range ladders {
+		d.Currencies[currency] = segments(currency, amounts)
+	}
+	return d
+}
+
+// segments compresses ascending points into (first, last, step) segments of
+// evenly spaced points
+// Example: 0.29, 0.39, 0.49, 0.99 becomes [0.29 0.49 0.10] [0.99 0.99 0.01]
+func segments(currency string, amounts []int64) [][3]string {
+	decimal := func(amount int64) string {
+		return ppp.NewMoney(amount, currency).Decimal()
+	}
+	unit := decimal(1)
+	
+	var out [][3]string
+	for i := 0; i < len(amounts); {
+		if i+1 == len(amounts) {
+			out = append(out, [3]string{decimal(amounts[i]), decimal(amounts[i]), unit})
+			break
+		}
+		step := amounts[i+1] - amounts[i]
+		j := i + 1
+		for j+1 < len(amounts) && amounts[j+1]-amounts[j] == step {
+			j++
+		}
+		out = append(out, [3]string{decimal(amounts[i]), decimal(amounts[j]), decimal(step)})
+		i = j + 1
+	}
+	return out
+}
+
+// readGooglePlay reads a currency,min_price,max_price CSV into one segment
+// per currency stepping by the currency's minor unit
+func readGooglePlay(r io.Reader) (map[string][][3]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != "currency,min_price,max_price" {
+		return nil, errors.New("expected a currency,min_price,max_price header")
+	}
+	
+	ranges := make(map[string][][3]string, len(records)-1)
+	for line, record := range records[1:] {
+		currency := strings.ToUpper(strings.TrimSpace(record[0]))
+		low, err := ppp.ParseMoney(record[1], currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		high, err := ppp.ParseMoney(record[2], currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		if low.Amount <= 0 || high.Amount < low.Amount {
+			return nil, fmt.Errorf("line %d: invalid range %s to %s", line+2, low, high)
+		}
+		if _, ok := ranges[currency]; ok {
+			return nil, fmt.Errorf("line %d: duplicate currency %s", line+2, currency)
+		}
+		ranges[currency] = [][3]string{{low.Decimal(), high.Decimal(), ppp.NewMoney(1, currency).Decimal()}}
+	}
+	return ranges, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{
+		"-appstore", "testdata/appstore-*.json", "-appstore-version", "2025-06",
+		"-googleplay", "testdata/googleplay.csv", "-googleplay-version", "2025-06",
+		"-retrieved", "2025-06-12",
+	}
+	if err := run(args, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var data map[string]storeData
+	if err := json.Unmarshal(out.Bytes(), &data); err != nil {
+		t.Fatalf("Invalid output: %v\n%s", err, out.String())
+	}
+
+	appStore := data["appstore"]
+	if appStore.Version != "2025-06" || appStore.Retrieved != "2025-06-12" || appStore.Source != appStoreSource {
+		t.Errorf("Unexpected App Store metadata %+v", appStore)
+	}
+	want := map[string][][3]string{
+		"USD": {{"0.29", "0.59", "0.10"}, {"0.99", "1.99", "0.50"}},
+		// DEU lacks 0.59, so the euro ladder doesn't have it
+		"EUR": {{"0.29", "0.49", "0.10"}, {"0.99", "1.99", "0.50"}},
+		"JPY": {{"50", "150", "50"}, {"160", "170", "10"}},
+	}
+	if !reflect.DeepEqual(appStore.Currencies, want) {
+		t.Errorf("App Store ladders = %v, want %v", appStore.Currencies, want)
+	}
+
+	googlePlay := data["googleplay"]
+	wantPlay := map[string][][3]string{
+		"USD": {{"0.49", "400.00", "0.01"}},
+		"JPY": {{"30", "60000", "1"}},
+	}
+	if googlePlay.Source != googlePlaySource || !reflect.DeepEqual(googlePlay.Currencies, wantPlay) {
+		t.Errorf("Google Play data = %+v, want %v", googlePlay, wantPlay)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	got := segments("USD", []int64{29, 39, 49, 99, 149, 500})
+	want := [][3]string{{"0.29", "0.49", "0.10"}, {"0.99", "1.49", "0.50"}, {"5.00", "5.00", "0.01"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("segments = %v, want %v", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-appstore", "testdata/appstore-1.json", "-appstore-version", "1"}, "-retrieved"},
+		{[]string{"-retrieved", "2025-06-12"}, "expected -appstore"},
+		{[]string{"-appstore", "testdata/missing-*.json", "-appstore-version", "1", "-retrieved", "2025-06-12"}, "no files match"},
+		{[]string{"-googleplay", "testdata/appstore-1.json", "-googleplay-version", "1", "-retrieved", "2025-06-12"}, "testdata/appstore-1.json"},
+	}
+	for _, tt := range tests {
+		if err := run(tt.args, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("run(%v) = %v, want an error containing %q", tt.args, err, tt.want)
+		}
+	}
+}
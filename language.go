@@ -0,0 +1,216 @@
+package ppp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageRange is a weighted language range from an Accept-Language header
+type LanguageRange struct {
+	Tag     string  // canonical BCP 47 tag, e.g. "pt-BR", or "*"
+	Quality float64 // q-weight from 0 to 1
+}
+
+// ParseAcceptLanguage parses an Accept-Language header into language ranges
+// sorted by descending quality, keeping header order for equal weights
+// Malformed ranges, repeated ranges and ranges with q=0 are dropped
+// Example: ParseAcceptLanguage("pt-BR,pt;q=0.9,en;q=0.8") returns pt-BR, pt, en
+func ParseAcceptLanguage(header string) []LanguageRange {
+	var ranges []LanguageRange
+	seen := make(map[string]bool)
+	
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag, ok := canonicalRange(tag)
+		if !ok {
+			continue
+		}
+		
+		q, ok := parseQuality(params)
+		if !ok || q == 0 || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		
+		ranges = append(ranges, LanguageRange{Tag: tag, Quality: q})
+	}
+	
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+	return ranges
+}
+
+// parseQuality reads the q parameter of a language range, 1 if absent
+func parseQuality(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0, false
+		}
+		return q, true
+	}
+	return 1, true
+}
+
+// canonicalRange validates a basic language range (RFC 4647 section 2.1)
+// and canonicalizes its case, accepting POSIX underscores
+// Example: "zh_hant_tw" becomes "zh-Hant-TW"
+func canonicalRange(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return tag, true
+	}
+	
+	subtags := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	if len(subtags[0]) == 0 || len(subtags[0]) > 8 || !isAlpha(subtags[0]) {
+		return "", false
+	}
+	
+	subtags[0] = strings.ToLower(subtags[0])
+	singleton := false
+	for i, subtag := range subtags[1:] {
+		if len(subtag) == 0 || len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return "", false
+		}
+		
+		// Subtags after an extension or private use singleton keep lower case
+		switch {
+		case singleton:
+			subtags[i+1] = strings.ToLower(subtag)
+		case len(subtag) == 1:
+			singleton = true
+			subtags[i+1] = strings.ToLower(subtag)
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[i+1] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2:
+			subtags[i+1] = strings.ToUpper(subtag)
+		default:
+			subtags[i+1] = strings.ToLower(subtag)
+		}
+	}
+	
+	return strings.Join(subtags, "-"), true
+}
+
+// isAlphanumeric reports whether s contains only ASCII letters and digits
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// LocaleRegion returns the country of a locale's region subtag
+// UN M.49 areas such as "419" (Latin America) are not countries
+// Example: LocaleRegion("pt-BR") returns "BR", true
+func LocaleRegion(tag string) (string, bool) {
+	_, _, region := splitLocale(tag)
+	if region == "" {
+		return "", false
+	}
+	country, err := NormalizeCountryCode(region)
+	if err != nil {
+		return "", false
+	}
+	return country, true
+}
+
+// CountryFromAcceptLanguage returns the country of the most preferred
+// language range that has a region subtag. Ranges without a region, such as
+// "de", are skipped as they don't identify a country
+// Example: CountryFromAcceptLanguage("en;q=0.9, pt-BR") returns "BR", true
+func CountryFromAcceptLanguage(header string) (string, bool) {
+	for _, r := range ParseAcceptLanguage(header) {
+		if country, ok := LocaleRegion(r.Tag); ok {
+			return country, true
+		}
+	}
+	return "", false
+}
+
+// NegotiateLocale picks the supported locale that best matches an
+// Accept-Language header. Each range, in order of preference, is matched by
+// RFC 4647 lookup (zh-Hant-TW, then zh-Hant, then zh) and then against
+// supported locales that extend it ("en" matches "en-US"). The supported
+// locale is returned as given; false means nothing matched
+// Example: NegotiateLocale("de-AT,en;q=0.5", []string{"en-US", "de"}) returns "de", true
+func NegotiateLocale(header string, supported []string) (string, bool) {
+	index := make(map[string]string, len(supported))
+	var canonical []string
+	for _, s := range supported {
+		tag, ok := canonicalRange(s)
+		if !ok || tag == "*" {
+			continue
+		}
+		if _, dup := index[tag]; !dup {
+			index[tag] = s
+			canonical = append(canonical, tag)
+		}
+	}
+	
+	for _, r := range ParseAcceptLanguage(header) {
+		if r.Tag == "*" {
+			continue
+		}
+		
+		for tag := r.Tag; tag != ""; tag = truncateRange(tag) {
+			if s, ok := index[tag]; ok {
+				return s, true
+			}
+		}
+		
+		for _, tag := range canonical {
+			if strings.HasPrefix(tag, r.Tag+"-") {
+				return index[tag], true
+			}
+		}
+	}
+	
+	return "", false
+}
+
+// truncateRange drops the last subtag of a range, along with a singleton
+// left at the end, as in RFC 4647 lookup
+func truncateRange(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if i := strings.LastIndex(tag, "-"); i >= 0 && len(tag)-i == 2 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// PreferredLocale returns the most preferred language range in an
+// Accept-Language header whose language has formatting data, for use with
+// FormatPriceLocale. The range keeps its region so the formatted symbol
+// matches it. Returns DefaultLocale if no language is supported
+// Example: PreferredLocale("xx, fr-CA;q=0.8") returns "fr-CA"
+func PreferredLocale(header string) string {
+	formats := loadLocales()
+	for _, r := range ParseAcceptLanguage(header) {
+		if r.Tag == "*" {
+			continue
+		}
+		
+		lang, _, _ := splitLocale(r.Tag)
+		if alias, ok := localeAliases[lang]; ok {
+			lang = alias
+		}
+		if _, ok := formats[lang]; ok {
+			return r.Tag
+		}
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,96 @@
+package ppp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []LanguageRange
+	}{
+		{"pt-BR,pt;q=0.9,en;q=0.8", []LanguageRange{{"pt-BR", 1}, {"pt", 0.9}, {"en", 0.8}}},
+		{"en;q=0.5, DE-de, fr;q=0.7", []LanguageRange{{"de-DE", 1}, {"fr", 0.7}, {"en", 0.5}}},
+		{"zh_hant_tw, *;q=0.1", []LanguageRange{{"zh-Hant-TW", 1}, {"*", 0.1}}},
+		{"es-419;Q=0.9, en-x-Private", []LanguageRange{{"en-x-private", 1}, {"es-419", 0.9}}},
+		{"en, en;q=0.2, fr;q=0, de;q=2, it;q=abc, 12, en-", []LanguageRange{{"en", 1}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCountryFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"pt-BR,pt;q=0.9", "BR"},
+		{"en;q=0.9, de-AT", "AT"},
+		{"de, fr-CH;q=0.5", "CH"},
+		{"es-419, es-MX;q=0.8", "MX"},
+		{"en-UK", "GB"},
+		{"zh-Hant-TW", "TW"},
+		{"de, en", ""},
+		{"xx-ZZ", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := CountryFromAcceptLanguage(tt.header)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("CountryFromAcceptLanguage(%q) = %q, %v; want %q", tt.header, got, ok, tt.want)
+		}
+	}
+
+	if country, ok := LocaleRegion("pt_BR.UTF-8"); !ok || country != "BR" {
+		t.Errorf("LocaleRegion(pt_BR.UTF-8) = %q, %v; want BR", country, ok)
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	supported := []string{"en-US", "de", "pt-BR", "pt-PT", "zh-Hant", "fr"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"de-AT,en;q=0.5", "de"},
+		{"pt-br", "pt-BR"},
+		{"pt", "pt-BR"},
+		{"en", "en-US"},
+		{"zh-Hant-TW", "zh-Hant"},
+		{"ja, fr;q=0.3", "fr"},
+		{"de-CH-x-phonebk", "de"},
+		{"ja, *", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := NegotiateLocale(tt.header, supported)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("NegotiateLocale(%q) = %q, %v; want %q", tt.header, got, ok, tt.want)
+		}
+	}
+
+	if got, _ := NegotiateLocale("en-gb", []string{"en_GB"}); got != "en_GB" {
+		t.Errorf("Expected the supported locale as given, got %q", got)
+	}
+}
+
+func TestPreferredLocale(t *testing.T) {
+	tests := map[string]string{
+		"xx, fr-CA;q=0.8": "fr-CA",
+		"de-LU":           "de-LU",
+		"no-NO":           "no-NO",
+		"":                DefaultLocale,
+		"xx, *":           DefaultLocale,
+	}
+	for header, want := range tests {
+		if got := PreferredLocale(header); got != want {
+			t.Errorf("PreferredLocale(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
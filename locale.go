@@ -0,0 +1,325 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// localeData holds number and currency conventions derived from CLDR
+//go:embed data/locales.json
+var localeData []byte
+
+// DefaultLocale is used when a locale is not in the dataset
+const DefaultLocale = "en"
+
+// localeFormat describes how a locale formats currency amounts
+type localeFormat struct {
+	Locale      string `json:"locale"`
+	Region      string `json:"region"`       // default region of the locale
+	Decimal     string `json:"decimal"`      // decimal separator
+	Group       string `json:"group"`        // grouping separator
+	Pattern     string `json:"pattern"`      // CLDR currency pattern, e.g. "#,##0.00 ¤"
+	Minus       string `json:"minus"`        // minus sign, "-" if empty
+	MinGrouping int    `json:"min_grouping"` // minimum integer digits before grouping applies
+	
+	positive numberPattern
+	negative numberPattern
+}
+
+// numberPattern is a parsed CLDR number pattern
+type numberPattern struct {
+	prefix    string
+	suffix    string
+	primary   int // size of the rightmost digit group, 0 for no grouping
+	secondary int // size of the other digit groups
+}
+
+var (
+	localesOnce   sync.Once
+	locales       map[string]*localeFormat
+	localeAliases map[string]string
+)
+
+// loadLocales parses the embedded locale data once
+func loadLocales() map[string]*localeFormat {
+	localesOnce.Do(func() {
+		var data struct {
+			Aliases map[string]string `json:"aliases"`
+			Locales []*localeFormat   `json:"locales"`
+		}
+		if err := json.Unmarshal(localeData, &data); err != nil {
+			panic("ppp: invalid embedded locale data: " + err.Error())
+		}
+		
+		locales = make(map[string]*localeFormat, len(data.Locales))
+		for _, f := range data.Locales {
+			if f.Minus == "" {
+				f.Minus = "-"
+			}
+			if f.MinGrouping == 0 {
+				f.MinGrouping = 1
+			}
+			
+			positive, negative, hasNegative := strings.Cut(f.Pattern, ";")
+			f.positive = parseNumberPattern(positive, f.Minus)
+			if hasNegative {
+				f.negative = parseNumberPattern(negative, f.Minus)
+			} else {
+				// CLDR default: the minus sign in front of the positive pattern
+				f.negative = f.positive
+				f.negative.prefix = f.Minus + f.positive.prefix
+			}
+			
+			locales[strings.ToLower(f.Locale)] = f
+		}
+		localeAliases = data.Aliases
+	})
+	return locales
+}
+
+// parseNumberPattern splits a pattern into affixes and grouping sizes
+func parseNumberPattern(pattern, minus string) numberPattern {
+	start := strings.IndexAny(pattern, "#0")
+	end := strings.LastIndexAny(pattern, "#0") + 1
+	if start < 0 {
+		return numberPattern{prefix: pattern}
+	}
+	
+	p := numberPattern{
+		prefix: strings.ReplaceAll(pattern[:start], "-", minus),
+		suffix: strings.ReplaceAll(pattern[end:], "-", minus),
+	}
+	
+	integer, _, _ := strings.Cut(pattern[start:end], ".")
+	groups := strings.Split(integer, ",")
+	if len(groups) > 1 {
+		p.primary = len(groups[len(groups)-1])
+		p.secondary = p.primary
+		if len(groups) > 2 {
+			p.secondary = len(groups[len(groups)-2])
+		}
+	}
+	
+	return p
+}
+
+// resolveLocale finds the closest locale in the dataset and the region to use
+// Example: "de_CH" resolves to de-CH; "de-LU" falls back to de with region LU
+func resolveLocale(tag string) (*localeFormat, string) {
+	formats := loadLocales()
+	
+	lang, script, region := splitLocale(tag)
+	if alias, ok := localeAliases[lang]; ok {
+		lang = alias
+	}
+	
+	var candidates []string
+	if script != "" && region != "" {
+		candidates = append(candidates, lang+"-"+script+"-"+region)
+	}
+	if region != "" {
+		candidates = append(candidates, lang+"-"+region)
+	}
+	if script != "" {
+		candidates = append(candidates, lang+"-"+script)
+	}
+	candidates = append(candidates, lang)
+	
+	for _, candidate := range candidates {
+		if alias, ok := localeAliases[candidate]; ok {
+			candidate = alias
+		}
+		if f, ok := formats[strings.ToLower(candidate)]; ok {
+			if region == "" || isDigits(region) {
+				region = f.Region
+			}
+			return f, region
+		}
+	}
+	
+	f := formats[DefaultLocale]
+	if region == "" {
+		region = f.Region
+	}
+	return f, region
+}
+
+// splitLocale splits a BCP 47 or POSIX locale into language, script and region
+func splitLocale(tag string) (lang, script, region string) {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		// POSIX encoding and modifier, e.g. de_DE.UTF-8
+		tag = tag[:i]
+	}
+	
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return DefaultLocale, "", ""
+	}
+	
+	lang = strings.ToLower(parts[0])
+	for _, part := range parts[1:] {
+		switch {
+		case len(part) == 1:
+			// Extensions and private use subtags end the interesting part
+			return lang, script, region
+		case len(part) == 4 && script == "" && region == "" && isAlpha(part):
+			script = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case (len(part) == 2 && isAlpha(part)) || (len(part) == 3 && isDigits(part)):
+			if region == "" {
+				region = strings.ToUpper(part)
+			}
+		}
+	}
+	
+	return lang, script, region
+}
+
+// FormatPriceLocale formats a price for a locale using CLDR conventions
+// Separators, digit grouping (including Indian lakh grouping), symbol placement
+// and spacing, and negative formats come from the locale; decimals come from
+// the currency. Unknown locales fall back to their language, then to English
+// Example: FormatPriceLocale(1155, "EUR", "de-DE") returns "1.155,00 €"
+func FormatPriceLocale(amount float64, currency, locale string) string {
+	currency = strings.ToUpper(currency)
+	f, region := resolveLocale(locale)
+	
+	rounded := RoundPrice(amount, currency)
+	pattern := f.positive
+	if rounded < 0 {
+		pattern = f.negative
+	}
+	
+	number := formatDigits(math.Abs(rounded), currencyCashDigits(currency), f, pattern)
+	symbol := localeCurrencySymbol(currency, region)
+	
+	return applyAffix(pattern.prefix, symbol, true) + number + applyAffix(pattern.suffix, symbol, false)
+}
+
+// FormatNumberLocale formats a number with a locale's separators and grouping
+// Example: FormatNumberLocale(1234567.891, 2, "en-IN") returns "12,34,567.89"
+func FormatNumberLocale(value float64, decimals int, locale string) string {
+	f, _ := resolveLocale(locale)
+	
+	scale := math.Pow10(decimals)
+	rounded := math.Round(value*scale) / scale
+	
+	number := formatDigits(math.Abs(rounded), decimals, f, f.positive)
+	if rounded < 0 {
+		return f.Minus + number
+	}
+	return number
+}
+
+// FormatLocale formats the amount for a locale, like FormatPriceLocale
+func (m Money) FormatLocale(locale string) string {
+	return FormatPriceLocale(m.Float64(), m.Currency, locale)
+}
+
+// formatDigits formats a non-negative number with separators and grouping
+func formatDigits(value float64, decimals int, f *localeFormat, pattern numberPattern) string {
+	s := strconv.FormatFloat(value, 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+	
+	var b strings.Builder
+	b.WriteString(groupDigits(integer, pattern.primary, pattern.secondary, f.MinGrouping, f.Group))
+	if fraction != "" {
+		b.WriteString(f.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// groupDigits inserts grouping separators into an integer
+func groupDigits(integer string, primary, secondary, minGrouping int, sep string) string {
+	if primary <= 0 || len(integer) < primary+minGrouping {
+		return integer
+	}
+	
+	groups := []string{integer[len(integer)-primary:]}
+	rest := integer[:len(integer)-primary]
+	for len(rest) > secondary {
+		groups = append(groups, rest[len(rest)-secondary:])
+		rest = rest[:len(rest)-secondary]
+	}
+	if rest != "" {
+		groups = append(groups, rest)
+	}
+	
+	// Reverse into reading order
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return strings.Join(groups, sep)
+}
+
+// applyAffix replaces the currency sign in a prefix or suffix with the symbol
+// Following CLDR currency spacing, a no-break space separates a symbol that
+// ends (or starts) with a letter from the digits, e.g. "CHF 100.00"
+func applyAffix(affix, symbol string, prefix bool) string {
+	i := strings.Index(affix, "¤")
+	if i < 0 {
+		return affix
+	}
+	
+	if prefix && i+len("¤") == len(affix) {
+		if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) {
+			symbol += " "
+		}
+	} else if !prefix && i == 0 {
+		if r, _ := utf8.DecodeRuneInString(symbol); unicode.IsLetter(r) {
+			symbol = " " + symbol
+		}
+	}
+	
+	return affix[:i] + symbol + affix[i+len("¤"):]
+}
+
+// localeCurrencySymbol picks the symbol for a currency in a region
+// A region's own currency uses its narrow symbol ("$" for CAD in Canada);
+// other currencies use the unambiguous symbol ("C$" elsewhere)
+func localeCurrencySymbol(currency, region string) string {
+	c, ok := LookupCurrency(currency)
+	if !ok {
+		return currency
+	}
+	
+	if c.NarrowSymbol != "" {
+		for _, country := range c.Countries {
+			if country == region {
+				return c.NarrowSymbol
+			}
+		}
+	}
+	
+	if c.Symbol != "" {
+		return c.Symbol
+	}
+	return c.Code
+}
+
+// isAlpha reports whether s contains only ASCII letters
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s contains only ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
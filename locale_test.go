@@ -0,0 +1,69 @@
+package ppp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatPriceLocale(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		locale   string
+		want     string
+	}{
+		{1155, "EUR", "de-DE", "1.155,00 €"},
+		{1155, "EUR", "de_DE.UTF-8", "1.155,00 €"},
+		{1234567.5, "INR", "en-IN", "₹12,34,567.50"},
+		{1234567.5, "INR", "hi", "₹12,34,567.50"},
+		{1155, "USD", "en-US", "$1,155.00"},
+		{-1155, "USD", "en", "-$1,155.00"},
+		{1155, "CAD", "en-CA", "$1,155.00"},
+		{1155, "CAD", "en-US", "C$1,155.00"},
+		{1155, "CHF", "en", "CHF 1,155.00"},
+		{-1155, "CHF", "de-CH", "CHF-1’155.00"},
+		{1155.5, "TRY", "tr-TR", "₺1.155,50"},
+		{1155, "EUR", "es-ES", "1155,00 €"},
+		{11550, "EUR", "es", "11.550,00 €"},
+		{1155, "EUR", "fr-FR", "1 155,00 €"},
+		{-1155, "SEK", "sv-SE", "−1 155,00 kr"},
+		{1155, "BRL", "pt-BR", "R$ 1.155,00"},
+		{150000, "JPY", "ja-JP", "¥150,000"},
+		{1155, "KWD", "en", "KD 1,155.000"},
+		{1155, "EUR", "de-LU", "1.155,00 €"},
+		{1155, "MXN", "es-419", "$1,155.00"},
+		{1155, "USD", "xx-YY", "$1,155.00"},
+	}
+
+	for _, tt := range tests {
+		// CLDR uses no-break spaces; compare with plain spaces for readability
+		got := strings.NewReplacer(" ", " ", " ", " ").Replace(FormatPriceLocale(tt.amount, tt.currency, tt.locale))
+		if got != tt.want {
+			t.Errorf("FormatPriceLocale(%v, %s, %s) = %q, want %q", tt.amount, tt.currency, tt.locale, got, tt.want)
+		}
+	}
+
+	if got := FormatPriceLocale(1155, "EUR", "de"); got != "1.155,00 €" {
+		t.Errorf("Expected a no-break space before the symbol, got %q", got)
+	}
+}
+
+func TestFormatNumberLocale(t *testing.T) {
+	tests := []struct {
+		value    float64
+		decimals int
+		locale   string
+		want     string
+	}{
+		{1234567.891, 2, "en-IN", "12,34,567.89"},
+		{1234567.891, 0, "de", "1.234.568"},
+		{-0.5, 1, "fi", "−0,5"},
+		{999, 2, "en", "999.00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatNumberLocale(tt.value, tt.decimals, tt.locale); got != tt.want {
+			t.Errorf("FormatNumberLocale(%v, %d, %s) = %q, want %q", tt.value, tt.decimals, tt.locale, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// countryMetadataData is a snapshot of the World Bank country list
+//go:embed data/countries.json
+var countryMetadataData []byte
+
+// CountryMetadata describes a World Bank economy
+// Like Country, but with numeric coordinates and the local currency
+type CountryMetadata struct {
+	Code        string  `json:"code"`         // ISO 3166-1 alpha-2 code, e.g. "TR"
+	ID          string  `json:"id"`           // World Bank ID (alpha-3), e.g. "TUR"
+	Name        string  `json:"name"`         // World Bank name, e.g. "Turkiye"
+	Region      Region  `json:"region"`       // e.g. Europe & Central Asia
+	IncomeLevel Level   `json:"income_level"` // e.g. Upper middle income
+	LendingType Level   `json:"lending_type"` // e.g. IBRD
+	CapitalCity string  `json:"capital_city"`
+	Currency    string  `json:"currency"`  // ISO 4217 code used for pricing, e.g. "TRY"
+	Latitude    float64 `json:"latitude"`  // capital city latitude
+	Longitude   float64 `json:"longitude"` // capital city longitude
+}
+
+// worldBankRegions are the World Bank regions by ID
+var worldBankRegions = map[string]Region{
+	"EAS": {ID: "EAS", ISO2Code: "Z4", Value: "East Asia & Pacific"},
+	"ECS": {ID: "ECS", ISO2Code: "Z7", Value: "Europe & Central Asia"},
+	"LCN": {ID: "LCN", ISO2Code: "ZJ", Value: "Latin America & Caribbean"},
+	"MEA": {ID: "MEA", ISO2Code: "ZQ", Value: "Middle East, North Africa, Afghanistan & Pakistan"},
+	"NAC": {ID: "NAC", ISO2Code: "XU", Value: "North America"},
+	"SAS": {ID: "SAS", ISO2Code: "8S", Value: "South Asia"},
+	"SSF": {ID: "SSF", ISO2Code: "ZG", Value: "Sub-Saharan Africa "},
+}
+
+// worldBankLevels are the World Bank income levels and lending types by ID
+var worldBankLevels = map[string]Level{
+	"LIC": {ID: "LIC", ISO2Code: "XM", Value: "Low income"},
+	"LMC": {ID: "LMC", ISO2Code: "XN", Value: "Lower middle income"},
+	"UMC": {ID: "UMC", ISO2Code: "XT", Value: "Upper middle income"},
+	"HIC": {ID: "HIC", ISO2Code: "XD", Value: "High income"},
+	"INX": {ID: "INX", ISO2Code: "XY", Value: "Not classified"},
+	"IBD": {ID: "IBD", ISO2Code: "XF", Value: "IBRD"},
+	"IDB": {ID: "IDB", ISO2Code: "XH", Value: "Blend"},
+	"IDX": {ID: "IDX", ISO2Code: "XI", Value: "IDA"},
+	"LNX": {ID: "LNX", ISO2Code: "XX", Value: "Not classified"},
+}
+
+var (
+	countryMetadataOnce sync.Once
+	countryMetadata     []CountryMetadata
+	countryMetadataBy   map[string]*CountryMetadata
+)
+
+// loadCountryMetadata parses the embedded country list once
+func loadCountryMetadata() []CountryMetadata {
+	countryMetadataOnce.Do(func() {
+		var entries []struct {
+			ISO2     string  `json:"iso2"`
+			ID       string  `json:"id"`
+			Name     string  `json:"name"`
+			Region   string  `json:"region"`
+			Income   string  `json:"income"`
+			Lending  string  `json:"lending"`
+			Capital  string  `json:"capital"`
+			Currency string  `json:"currency"`
+			Lat      float64 `json:"lat"`
+			Lon      float64 `json:"lon"`
+		}
+		if err := json.Unmarshal(countryMetadataData, &entries); err != nil {
+			panic("ppp: invalid embedded country metadata: " + err.Error())
+		}
+		
+		countryMetadata = make([]CountryMetadata, len(entries))
+		countryMetadataBy = make(map[string]*CountryMetadata, len(entries))
+		for i, e := range entries {
+			countryMetadata[i] = CountryMetadata{
+				Code:        e.ISO2,
+				ID:          e.ID,
+				Name:        e.Name,
+				Region:      worldBankRegions[e.Region],
+				IncomeLevel: worldBankLevels[e.Income],
+				LendingType: worldBankLevels[e.Lending],
+				CapitalCity: e.Capital,
+				Currency:    e.Currency,
+				Latitude:    e.Lat,
+				Longitude:   e.Lon,
+			}
+			countryMetadataBy[e.ISO2] = &countryMetadata[i]
+		}
+	})
+	return countryMetadata
+}
+
+// LookupCountryMetadata returns the offline metadata of a World Bank economy
+// Accepts any code NormalizeCountryCode understands
+// Example: LookupCountryMetadata("tur") returns Turkiye with currency TRY
+func LookupCountryMetadata(code string) (CountryMetadata, bool) {
+	loadCountryMetadata()
+	normalized, err := NormalizeCountryCode(code)
+	if err != nil {
+		return CountryMetadata{}, false
+	}
+	m, ok := countryMetadataBy[normalized]
+	if !ok {
+		return CountryMetadata{}, false
+	}
+	return *m, true
+}
+
+// AllCountryMetadata returns the offline metadata of all economies, sorted by name
+func AllCountryMetadata() []CountryMetadata {
+	return append([]CountryMetadata(nil), loadCountryMetadata()...)
+}
+
+// Country converts the metadata to the World Bank Country type
+func (m CountryMetadata) Country() Country {
+	return Country{
+		ID:          m.ID,
+		ISO2Code:    m.Code,
+		Name:        m.Name,
+		Region:      m.Region,
+		IncomeLevel: m.IncomeLevel,
+		LendingType: m.LendingType,
+		CapitalCity: m.CapitalCity,
+		Longitude:   strconv.FormatFloat(m.Longitude, 'f', -1, 64),
+		Latitude:    strconv.FormatFloat(m.Latitude, 'f', -1, 64),
+	}
+}
+
+// Coordinates parses the capital city coordinates of a World Bank country
+// ok is false when the API returned no coordinates, as for aggregates
+func (c Country) Coordinates() (latitude, longitude float64, ok bool) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(c.Latitude), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(c.Longitude), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// offlineCountries returns the embedded country list as World Bank countries
+func offlineCountries() []Country {
+	metadata := loadCountryMetadata()
+	countries := make([]Country, len(metadata))
+	for i, m := range metadata {
+		countries[i] = m.Country()
+	}
+	return countries
+}
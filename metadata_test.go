@@ -0,0 +1,119 @@
+package ppp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLookupCountryMetadata(t *testing.T) {
+	tr, ok := LookupCountryMetadata("tur")
+	if !ok {
+		t.Fatal("Expected metadata for TUR")
+	}
+	if tr.Code != "TR" || tr.ID != "TUR" || tr.Currency != "TRY" || tr.CapitalCity != "Ankara" {
+		t.Errorf("Unexpected metadata %+v", tr)
+	}
+	if tr.Region.ID != "ECS" || tr.IncomeLevel.ID != "UMC" || tr.LendingType.Value != "IBRD" {
+		t.Errorf("Unexpected classification %+v", tr)
+	}
+	if tr.Latitude != 39.7153 || tr.Longitude != 32.3606 {
+		t.Errorf("Unexpected coordinates %v, %v", tr.Latitude, tr.Longitude)
+	}
+
+	if _, ok := LookupCountryMetadata("AQ"); ok {
+		t.Error("Antarctica is not a World Bank economy")
+	}
+
+	all := AllCountryMetadata()
+	if len(all) < 200 {
+		t.Errorf("Expected at least 200 economies, got %d", len(all))
+	}
+	for _, m := range all {
+		if _, ok := LookupCurrency(m.Currency); !ok {
+			t.Errorf("%s has unknown currency %q", m.Code, m.Currency)
+		}
+		if m.Region.ID == "" || m.IncomeLevel.ID == "" {
+			t.Errorf("%s is missing its region or income level", m.Code)
+		}
+	}
+
+	country := tr.Country()
+	if lat, lon, ok := country.Coordinates(); !ok || lat != tr.Latitude || lon != tr.Longitude {
+		t.Errorf("Coordinates() = %v, %v, %v", lat, lon, ok)
+	}
+	if _, _, ok := (Country{}).Coordinates(); ok {
+		t.Error("Expected no coordinates for an empty country")
+	}
+}
+
+func TestCountriesOffline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprint(w, `[{"page":1},[{"id":"TUR","iso2Code":"TR","name":"Turkiye","capitalCity":"Ankara"}]]`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient(WithWorldBankURL(server.URL))
+	countries, err := client.GetCountries(ctx)
+	if err != nil {
+		t.Fatalf("GetCountries failed: %v", err)
+	}
+	if len(countries) < 200 || atomic.LoadInt32(&requests) != 0 {
+		t.Errorf("Expected the embedded list without requests, got %d countries and %d requests", len(countries), requests)
+	}
+
+	// A refresh replaces the snapshot with the live list
+	if _, err := client.RefreshCountries(ctx); err != nil {
+		t.Fatalf("RefreshCountries failed: %v", err)
+	}
+	if countries, _ := client.GetCountries(ctx); len(countries) != 1 {
+		t.Errorf("Expected the refreshed live list, got %d countries", len(countries))
+	}
+
+	live := NewClient(WithWorldBankURL(server.URL), WithLiveCountries(), WithoutCache())
+	if countries, _ := live.GetCountries(ctx); len(countries) != 1 {
+		t.Errorf("Expected the live list, got %d countries", len(countries))
+	}
+}
+
+func TestCurrencyForCountry(t *testing.T) {
+	client := NewClient()
+	tests := map[string]string{
+		"TR": "TRY",
+		"KE": "KES",
+		"PA": "USD",
+		"SN": "XOF",
+		"AQ": "USD",
+		"BG": "EUR",
+	}
+	for country, want := range tests {
+		if got := client.getCurrencyForCountry(country); got != want {
+			t.Errorf("getCurrencyForCountry(%q) = %q, want %q", country, got, want)
+		}
+	}
+}
+
+func TestTimeZones(t *testing.T) {
+	if got := CountryUTCOffsets("IND"); len(got) != 1 || got[0] != 330 {
+		t.Errorf("CountryUTCOffsets(IND) = %v, want [330]", got)
+	}
+	if !CountryHasUTCOffset("US", -240) || !CountryHasUTCOffset("US", -300) || CountryHasUTCOffset("US", 180) {
+		t.Error("Expected US offsets to include EDT and EST only")
+	}
+	if CountryUTCOffsets("ZZ") != nil {
+		t.Error("Expected no offsets for an unknown country")
+	}
+
+	if country, ok := TimeZoneCountry("Europe/Istanbul"); !ok || country != "TR" {
+		t.Errorf("TimeZoneCountry(Europe/Istanbul) = %q, %v; want TR", country, ok)
+	}
+	if _, ok := TimeZoneCountry("UTC"); ok {
+		t.Error("Expected UTC to have no country")
+	}
+}
@@ -0,0 +1,193 @@
+package ppp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the request duration histogram buckets in seconds
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricsObserver collects request and cache metrics and exposes them in the
+// Prometheus text exposition format
+//
+// Metrics:
+//   ppp_upstream_requests_total{upstream,status}        counter
+//   ppp_upstream_request_duration_seconds{upstream}     histogram
+//   ppp_cache_lookups_total{family,result}              counter
+type MetricsObserver struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[[2]string]uint64
+	durations map[string]*histogram
+	lookups   map[[2]string]uint64
+}
+
+// histogram is a cumulative Prometheus histogram
+type histogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewMetricsObserver creates a metrics observer
+// Uses DefaultLatencyBuckets when no buckets are given
+func NewMetricsObserver(buckets ...float64) *MetricsObserver {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	
+	return &MetricsObserver{
+		buckets:   buckets,
+		requests:  make(map[[2]string]uint64),
+		durations: make(map[string]*histogram),
+		lookups:   make(map[[2]string]uint64),
+	}
+}
+
+// ObserveRequest implements Observer
+func (m *MetricsObserver) ObserveRequest(_ context.Context, event RequestEvent) {
+	status := "error"
+	if event.StatusCode != 0 {
+		status = strconv.Itoa(event.StatusCode)
+	}
+	
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.requests[[2]string{event.Upstream, status}]++
+	
+	h, ok := m.durations[event.Upstream]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[event.Upstream] = h
+	}
+	
+	seconds := event.Duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObserveCache implements Observer
+func (m *MetricsObserver) ObserveCache(_ context.Context, event CacheEvent) {
+	result := "miss"
+	if event.Hit {
+		result = "hit"
+	}
+	
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lookups[[2]string{event.Family, result}]++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (m *MetricsObserver) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	
+	fmt.Fprintln(cw, "# HELP ppp_upstream_requests_total Upstream HTTP requests by upstream and status.")
+	fmt.Fprintln(cw, "# TYPE ppp_upstream_requests_total counter")
+	for _, key := range sortedKeys(m.requests) {
+		fmt.Fprintf(cw, "ppp_upstream_requests_total{upstream=%s,status=%s} %d\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), m.requests[key])
+	}
+	
+	fmt.Fprintln(cw, "# HELP ppp_upstream_request_duration_seconds Upstream HTTP request duration.")
+	fmt.Fprintln(cw, "# TYPE ppp_upstream_request_duration_seconds histogram")
+	upstreams := make([]string, 0, len(m.durations))
+	for upstream := range m.durations {
+		upstreams = append(upstreams, upstream)
+	}
+	sort.Strings(upstreams)
+	for _, upstream := range upstreams {
+		h := m.durations[upstream]
+		label := quoteLabel(upstream)
+		
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(cw, "ppp_upstream_request_duration_seconds_bucket{upstream=%s,le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(cw, "ppp_upstream_request_duration_seconds_bucket{upstream=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(cw, "ppp_upstream_request_duration_seconds_sum{upstream=%s} %s\n",
+			label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(cw, "ppp_upstream_request_duration_seconds_count{upstream=%s} %d\n", label, h.count)
+	}
+	
+	fmt.Fprintln(cw, "# HELP ppp_cache_lookups_total Cache lookups by family and result.")
+	fmt.Fprintln(cw, "# TYPE ppp_cache_lookups_total counter")
+	for _, key := range sortedKeys(m.lookups) {
+		fmt.Fprintf(cw, "ppp_cache_lookups_total{family=%s,result=%s} %d\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), m.lookups[key])
+	}
+	
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.(*bufio.Writer).Flush()
+}
+
+// ServeHTTP serves the metrics, so the observer can be mounted at /metrics
+func (m *MetricsObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// sortedKeys returns the keys of a labelled counter in order
+func sortedKeys(counters map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// labelEscaper escapes label values for the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel quotes a label value
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+// countingWriter counts bytes written and remembers the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// Write implements io.Writer
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
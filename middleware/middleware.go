@@ -0,0 +1,376 @@
+// Package middleware provides net/http middleware that determines a visitor's
+// country and attaches a pricing context to the request
+//
+// The country comes from, in order: a query parameter or cookie override and
+// CDN country headers when enabled, the client IP resolved with a geo.Resolver,
+// and the region of the Accept-Language header
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/geo"
+)
+
+// Source tells how the visitor's country was determined
+type Source string
+
+// Country sources, in order of precedence
+const (
+	SourceOverride       Source = "override"
+	SourceHeader         Source = "header"
+	SourceGeoIP          Source = "geoip"
+	SourceAcceptLanguage Source = "accept-language"
+	SourceDefault        Source = "default"
+)
+
+// ErrNoClient is returned when a Pricing has no client for recommendations
+var ErrNoClient = errors.New("pricing has no client")
+
+// DefaultCountryHeaders are CDN headers carrying the visitor's country
+// They are not trusted unless passed to WithCountryHeaders, since any client
+// reaching you directly can send them
+var DefaultCountryHeaders = []string{
+	"CF-IPCountry",
+	"CloudFront-Viewer-Country",
+	"X-Vercel-IP-Country",
+	"X-AppEngine-Country",
+}
+
+// Conventional names for the override parameter and cookie, for WithOverride
+const (
+	DefaultQueryParam = "country"
+	DefaultCookieName = "ppp_country"
+)
+
+// Option configures the middleware
+type Option func(*config)
+
+type config struct {
+	client         *ppp.Client
+	resolver       geo.Resolver
+	headers        []string
+	queryParam     string
+	cookieName     string
+	cookieMaxAge   time.Duration
+	trustedProxies []netip.Prefix
+	defaultCountry string
+	locales        []string
+}
+
+// WithClient sets the client used for recommendations
+func WithClient(client *ppp.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithResolver sets the resolver for client IP addresses
+func WithResolver(resolver geo.Resolver) Option {
+	return func(c *config) {
+		c.resolver = resolver
+	}
+}
+
+// WithCountryHeaders sets the CDN country headers to trust; none are by
+// default. Only enable them when every request reaches you through that CDN
+// Example: WithCountryHeaders("CF-IPCountry")
+func WithCountryHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.headers = headers
+	}
+}
+
+// WithOverride sets the query parameter and cookie that let visitors choose
+// their country explicitly. An empty name disables that override
+// Overrides are off by default, since they outrank headers and GeoIP and any
+// visitor can set them. A valid query override is remembered in the cookie
+// Example: WithOverride(DefaultQueryParam, DefaultCookieName)
+func WithOverride(queryParam, cookieName string) Option {
+	return func(c *config) {
+		c.queryParam = queryParam
+		c.cookieName = cookieName
+	}
+}
+
+// WithoutOverride ignores query parameter and cookie overrides, the default
+func WithoutOverride() Option {
+	return WithOverride("", "")
+}
+
+// WithTrustedProxies sets the proxies whose X-Forwarded-For entries are
+// trusted. By default loopback and private addresses are trusted
+func WithTrustedProxies(prefixes ...netip.Prefix) Option {
+	return func(c *config) {
+		c.trustedProxies = prefixes
+	}
+}
+
+// WithDefaultCountry sets the country used when no other source has one
+func WithDefaultCountry(countryCode string) Option {
+	return func(c *config) {
+		c.defaultCountry = countryCode
+	}
+}
+
+// WithLocales sets the locales the application supports. Pricing.Locale is
+// negotiated against them, falling back to the first one
+// Example: WithLocales("en-US", "de", "pt-BR")
+func WithLocales(locales ...string) Option {
+	return func(c *config) {
+		c.locales = locales
+	}
+}
+
+// Pricing is the localized pricing context of a request
+type Pricing struct {
+	Country string            // ISO 3166-1 alpha-2 code, empty if unknown
+	Source  Source            // where Country came from
+	Locale  string            // display locale negotiated from Accept-Language, e.g. "pt-BR"
+	IP      netip.Addr        // client IP after X-Forwarded-For processing
+	Signals map[Source]string // every country signal found, for consistency checks
+	
+	client *ppp.Client
+}
+
+// contextKey is the context key for the pricing context
+type contextKey struct{}
+
+// NewContext returns a context carrying the pricing context
+func NewContext(ctx context.Context, p *Pricing) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the pricing context attached by the middleware
+func FromContext(ctx context.Context) (*Pricing, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Pricing)
+	return p, ok
+}
+
+// New returns middleware that attaches a Pricing to every request
+// Example: http.Handle("/pricing", middleware.New(middleware.WithResolver(db))(handler))
+func New(opts ...Option) func(http.Handler) http.Handler {
+	cfg := &config{
+		cookieMaxAge: 30 * 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.client == nil {
+		cfg.client = ppp.NewClient()
+	}
+	if cfg.defaultCountry != "" {
+		if code, err := ppp.NormalizeCountryCode(cfg.defaultCountry); err == nil {
+			cfg.defaultCountry = code
+		}
+	}
+	
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := cfg.resolve(w, r)
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+		})
+	}
+}
+
+// resolve gathers every country signal and picks the strongest
+func (cfg *config) resolve(w http.ResponseWriter, r *http.Request) *Pricing {
+	p := &Pricing{
+		Signals: make(map[Source]string),
+		client:  cfg.client,
+		IP:      cfg.clientIP(r),
+	}
+	
+	if country := cfg.override(w, r); country != "" {
+		p.Signals[SourceOverride] = country
+	}
+	
+	for _, header := range cfg.headers {
+		if country := validCountry(r.Header.Get(header)); country != "" {
+			p.Signals[SourceHeader] = country
+			break
+		}
+	}
+	
+	if cfg.resolver != nil && p.IP.IsValid() {
+		if country, err := cfg.resolver.Country(r.Context(), p.IP); err == nil {
+			p.Signals[SourceGeoIP] = country
+		}
+	}
+	
+	acceptLanguage := r.Header.Get("Accept-Language")
+	if country, ok := ppp.CountryFromAcceptLanguage(acceptLanguage); ok {
+		p.Signals[SourceAcceptLanguage] = country
+	}
+	p.Locale = cfg.locale(acceptLanguage)
+	
+	for _, source := range []Source{SourceOverride, SourceHeader, SourceGeoIP, SourceAcceptLanguage} {
+		if country, ok := p.Signals[source]; ok {
+			p.Country, p.Source = country, source
+			return p
+		}
+	}
+	
+	if cfg.defaultCountry != "" {
+		p.Country, p.Source = cfg.defaultCountry, SourceDefault
+	}
+	return p
+}
+
+// locale negotiates the display locale, against the supported locales if set
+func (cfg *config) locale(acceptLanguage string) string {
+	if len(cfg.locales) == 0 {
+		return ppp.PreferredLocale(acceptLanguage)
+	}
+	if locale, ok := ppp.NegotiateLocale(acceptLanguage, cfg.locales); ok {
+		return locale
+	}
+	return cfg.locales[0]
+}
+
+// override reads the query parameter or cookie override
+// A valid query override is stored in the cookie for later requests
+func (cfg *config) override(w http.ResponseWriter, r *http.Request) string {
+	if cfg.queryParam != "" {
+		if country := validCountry(r.URL.Query().Get(cfg.queryParam)); country != "" {
+			if cfg.cookieName != "" {
+				http.SetCookie(w, &http.Cookie{
+					Name:     cfg.cookieName,
+					Value:    country,
+					Path:     "/",
+					MaxAge:   int(cfg.cookieMaxAge.Seconds()),
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			return country
+		}
+	}
+	
+	if cfg.cookieName != "" {
+		if cookie, err := r.Cookie(cfg.cookieName); err == nil {
+			return validCountry(cookie.Value)
+		}
+	}
+	
+	return ""
+}
+
+// clientIP returns the client address, following X-Forwarded-For through
+// trusted proxies from the right
+func (cfg *config) clientIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	ip = ip.Unmap()
+	
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	
+	for i := len(hops) - 1; i >= 0 && cfg.trusted(ip); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		ip = hop.Unmap()
+	}
+	
+	return ip
+}
+
+// trusted reports whether ip is a trusted proxy
+func (cfg *config) trusted(ip netip.Addr) bool {
+	if cfg.trustedProxies == nil {
+		return ip.IsLoopback() || ip.IsPrivate()
+	}
+	for _, prefix := range cfg.trustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validCountry normalizes a country code, returning "" for unknown codes
+// such as Cloudflare's "XX" and "T1"
+func validCountry(code string) string {
+	if code = strings.TrimSpace(code); code == "" {
+		return ""
+	}
+	country, err := ppp.NormalizeCountryCode(code)
+	if err != nil {
+		return ""
+	}
+	return country
+}
+
+// RecommendationTimeout bounds recommendations made with a request context
+const RecommendationTimeout = 30 * time.Second
+
+// Recommend returns the price recommendation for the visitor's country
+func (p *Pricing) Recommend(ctx context.Context, price float64, fromCurrency string) (*ppp.PriceRecommendation, error) {
+	if p.Country == "" {
+		return nil, ppp.NewPPPError(
+			ppp.ErrCodeNoData,
+			"visitor country is unknown",
+			ppp.ErrInvalidCountry,
+		)
+	}
+	if p.client == nil {
+		return nil, ErrNoClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, RecommendationTimeout)
+	defer cancel()
+	return p.client.Recommend(ctx, price, fromCurrency, p.Country)
+}
+
+// RecommendMoney is Recommend with exact Money amounts
+func (p *Pricing) RecommendMoney(ctx context.Context, price ppp.Money) (*ppp.MoneyRecommendation, error) {
+	if p.Country == "" {
+		return nil, ppp.NewPPPError(
+			ppp.ErrCodeNoData,
+			"visitor country is unknown",
+			ppp.ErrInvalidCountry,
+		)
+	}
+	if p.client == nil {
+		return nil, ErrNoClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, RecommendationTimeout)
+	defer cancel()
+	return p.client.RecommendMoney(ctx, price, p.Country)
+}
+
+// Format formats a price in the visitor's locale
+func (p *Pricing) Format(amount float64, currency string) string {
+	return ppp.FormatPriceLocale(amount, currency, p.Locale)
+}
+
+// Recommend returns the recommendation for the request's visitor
+// The request must have passed through the middleware
+// Example: rec, err := middleware.Recommend(r, 49.99, "USD")
+func Recommend(r *http.Request, price float64, fromCurrency string) (*ppp.PriceRecommendation, error) {
+	p, ok := FromContext(r.Context())
+	if !ok {
+		return nil, ppp.NewPPPError(
+			ppp.ErrCodeNoData,
+			"request has no pricing context, is the middleware installed?",
+			nil,
+		)
+	}
+	return p.Recommend(r.Context(), price, fromCurrency)
+}
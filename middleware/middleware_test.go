@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/geo"
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+// staticResolver maps exact addresses to countries
+type staticResolver map[string]string
+
+func (s staticResolver) Country(_ context.Context, ip netip.Addr) (string, error) {
+	if country, ok := s[ip.String()]; ok {
+		return country, nil
+	}
+	return "", geo.ErrNotFound
+}
+
+// serve runs a request through the middleware and returns its pricing context
+func serve(t *testing.T, r *http.Request, opts ...Option) (*Pricing, *httptest.ResponseRecorder) {
+	t.Helper()
+	var pricing *Pricing
+	handler := New(append([]Option{WithClient(ppp.NewClient())}, opts...)...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pricing, _ = FromContext(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if pricing == nil {
+		t.Fatal("Expected a pricing context")
+	}
+	return pricing, w
+}
+
+func TestCountrySources(t *testing.T) {
+	resolver := staticResolver{"88.255.1.1": "TR", "8.8.8.8": "US"}
+
+	tests := []struct {
+		name    string
+		url     string
+		remote  string
+		headers map[string]string
+		cookie  string
+		country string
+		source  Source
+	}{
+		{"query override", "/?country=de", "88.255.1.1:1234", map[string]string{"CF-IPCountry": "FR"}, "BR", "DE", SourceOverride},
+		{"cookie override", "/", "88.255.1.1:1234", map[string]string{"CF-IPCountry": "FR"}, "BR", "BR", SourceOverride},
+		{"cdn header", "/", "88.255.1.1:1234", map[string]string{"CloudFront-Viewer-Country": "FR"}, "", "FR", SourceHeader},
+		{"unknown cdn country", "/", "88.255.1.1:1234", map[string]string{"CF-IPCountry": "XX"}, "", "TR", SourceGeoIP},
+		{"geoip", "/", "88.255.1.1:1234", nil, "", "TR", SourceGeoIP},
+		{"forwarded for", "/", "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "8.8.8.8, 10.0.0.2"}, "", "US", SourceGeoIP},
+		{"accept language", "/", "1.1.1.1:1234", map[string]string{"Accept-Language": "pt-BR,pt;q=0.9"}, "", "BR", SourceAcceptLanguage},
+		{"default", "/", "1.1.1.1:1234", map[string]string{"Accept-Language": "en"}, "", "US", SourceDefault},
+		{"invalid override", "/?country=ZZ", "88.255.1.1:1234", nil, "", "TR", SourceGeoIP},
+		{"untrusted cdn header", "/", "88.255.1.1:1234", map[string]string{"X-Custom-Country": "IN"}, "", "TR", SourceGeoIP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			r.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: tt.cookie})
+			}
+
+			p, _ := serve(t, r, WithResolver(resolver), WithDefaultCountry("us"), WithCountryHeaders(DefaultCountryHeaders...), WithOverride(DefaultQueryParam, DefaultCookieName))
+			if p.Country != tt.country || p.Source != tt.source {
+				t.Errorf("Got %s from %s, want %s from %s", p.Country, p.Source, tt.country, tt.source)
+			}
+		})
+	}
+}
+
+func TestCountryHeadersOptIn(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "88.255.1.1:1234"
+	r.Header.Set("CF-IPCountry", "IN")
+
+	p, _ := serve(t, r, WithResolver(staticResolver{"88.255.1.1": "TR"}))
+	if p.Country != "TR" || p.Source != SourceGeoIP {
+		t.Errorf("Expected CDN headers to be ignored by default, got %s from %s", p.Country, p.Source)
+	}
+	if _, ok := p.Signals[SourceHeader]; ok {
+		t.Errorf("Expected no header signal, got %v", p.Signals)
+	}
+
+	p, _ = serve(t, r, WithResolver(staticResolver{"88.255.1.1": "TR"}), WithCountryHeaders("CF-IPCountry"))
+	if p.Country != "IN" || p.Source != SourceHeader {
+		t.Errorf("Expected the enabled CDN header, got %s from %s", p.Country, p.Source)
+	}
+}
+
+func TestOverrideCookie(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?country=tr", nil)
+	_, w := serve(t, r, WithOverride(DefaultQueryParam, DefaultCookieName))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName || cookies[0].Value != "TR" {
+		t.Errorf("Expected the override to be remembered, got %v", cookies)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/?country=tr", nil)
+	p, w := serve(t, r, WithOverride(DefaultQueryParam, DefaultCookieName), WithoutOverride())
+	if p.Source == SourceOverride || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected overrides to be disabled, got %s", p.Source)
+	}
+}
+
+func TestOverrideOptIn(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?country=IN", nil)
+	r.RemoteAddr = "88.255.1.1:1234"
+	r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "IN"})
+
+	p, w := serve(t, r, WithResolver(staticResolver{"88.255.1.1": "TR"}))
+	if p.Country != "TR" || p.Source != SourceGeoIP {
+		t.Errorf("Expected overrides to be ignored by default, got %s from %s", p.Country, p.Source)
+	}
+	if _, ok := p.Signals[SourceOverride]; ok || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected no override signal or cookie, got %v", p.Signals)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		remote  string
+		xff     string
+		trusted []netip.Prefix
+		want    string
+	}{
+		{"203.0.113.5:80", "8.8.8.8", nil, "203.0.113.5"},
+		{"127.0.0.1:80", "8.8.8.8", nil, "8.8.8.8"},
+		{"10.0.0.1:80", "6.6.6.6, 8.8.8.8, 10.0.0.2", nil, "8.8.8.8"},
+		{"[::ffff:10.0.0.1]:80", "8.8.8.8", nil, "8.8.8.8"},
+		{"10.0.0.1:80", "8.8.8.8", []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}, "10.0.0.1"},
+		{"192.0.2.1:80", "garbage, 8.8.8.8", []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}, "8.8.8.8"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remote
+		r.Header.Set("X-Forwarded-For", tt.xff)
+
+		p, _ := serve(t, r, WithTrustedProxies(tt.trusted...))
+		if p.IP.String() != tt.want {
+			t.Errorf("RemoteAddr %s, X-Forwarded-For %q: IP = %s, want %s", tt.remote, tt.xff, p.IP, tt.want)
+		}
+	}
+}
+
+func TestSignals(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "88.255.1.1:1234"
+	r.Header.Set("CF-IPCountry", "US")
+	r.Header.Set("Accept-Language", "de-DE;q=0.8, fr-CH")
+
+	p, _ := serve(t, r, WithResolver(staticResolver{"88.255.1.1": "TR"}), WithCountryHeaders("CF-IPCountry"))
+	want := map[Source]string{SourceHeader: "US", SourceGeoIP: "TR", SourceAcceptLanguage: "CH"}
+	for source, country := range want {
+		if p.Signals[source] != country {
+			t.Errorf("Signals[%s] = %q, want %s", source, p.Signals[source], country)
+		}
+	}
+	if p.Locale != "fr-CH" {
+		t.Errorf("Locale = %q, want the highest weighted tag fr-CH", p.Locale)
+	}
+}
+
+func TestLocales(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		locales        []string
+		want           string
+	}{
+		{"pt-BR,pt;q=0.9", nil, "pt-BR"},
+		{"xx-YY, de-AT;q=0.5", nil, "de-AT"},
+		{"de-AT, en;q=0.5", []string{"en-US", "de"}, "de"},
+		{"ja", []string{"en-US", "de"}, "en-US"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", tt.acceptLanguage)
+
+		p, _ := serve(t, r, WithLocales(tt.locales...))
+		if p.Locale != tt.want {
+			t.Errorf("Accept-Language %q with %v: Locale = %q, want %s", tt.acceptLanguage, tt.locales, p.Locale, tt.want)
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	client := ppptest.NewClient(t)
+	var rec *ppp.PriceRecommendation
+	var recErr error
+	handler := New(WithClient(client), WithCountryHeaders("CF-IPCountry"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec, recErr = Recommend(r, 100, "USD")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("CF-IPCountry", "TR")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if recErr != nil {
+		t.Fatalf("Recommend failed: %v", recErr)
+	}
+	if rec.TargetCurrency != "TRY" || math.Abs(rec.RecommendedPrice-1155) > 1e-9 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !errors.Is(recErr, ppp.ErrInvalidCountry) {
+		t.Errorf("Expected ErrInvalidCountry without a country, got %v", recErr)
+	}
+
+	if _, err := Recommend(httptest.NewRequest(http.MethodGet, "/", nil), 100, "USD"); err == nil {
+		t.Error("Expected an error without the middleware")
+	}
+
+	pricing := &Pricing{Country: "TR"}
+	r = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(NewContext(context.Background(), pricing))
+	if _, err := Recommend(r, 100, "USD"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("Expected ErrNoClient without a client, got %v", err)
+	}
+	if _, err := pricing.RecommendMoney(context.Background(), ppp.NewMoney(10000, "USD")); !errors.Is(err, ErrNoClient) {
+		t.Errorf("Expected ErrNoClient from RecommendMoney, got %v", err)
+	}
+}
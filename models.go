@@ -22,6 +22,13 @@ type ExchangeRate struct {
 	LastUpdated  time.Time `json:"last_updated"`
 }
 
+// RateTable holds all exchange rates from a base currency
+type RateTable struct {
+	Base        string             `json:"base"`
+	Rates       map[string]float64 `json:"rates"`
+	LastUpdated time.Time          `json:"last_updated"`
+}
+
 // PriceRecommendation represents a recommended price based on PPP
 type PriceRecommendation struct {
 	OriginalPrice      float64 `json:"original_price"`
@@ -31,6 +38,23 @@ type PriceRecommendation struct {
 	PPPFactor          float64 `json:"ppp_factor"`
 	ExchangeRate       float64 `json:"exchange_rate"`
 	DiscountPercentage float64 `json:"discount_percentage"`
+	
+	// Data vintages used for the recommendation
+	PPPYear          int        `json:"ppp_year,omitempty"`
+	ExchangeRateDate *time.Time `json:"exchange_rate_date,omitempty"`
+	AsOf             *time.Time `json:"as_of,omitempty"`
+	
+	// Store price point the price was snapped to, if any
+	PricePoint *PricePointSnap `json:"price_point,omitempty"`
+}
+
+// MoneyRecommendation is a PriceRecommendation with exact Money amounts
+type MoneyRecommendation struct {
+	Original    Money `json:"original"`
+	Recommended Money `json:"recommended"`
+	MarketPrice Money `json:"market_price"` // price at the market exchange rate
+	
+	*PriceRecommendation
 }
 
 // Country represents World Bank country data
@@ -96,6 +120,16 @@ type IndicatorData struct {
 	Decimal          int           `json:"decimal"`
 }
 
+// CountryAlpha2 returns the ISO alpha-2 code of the data point's country
+// Maps CountryISO3Code back through the ISO 3166 registry, falling back to
+// the World Bank country ID for aggregates
+func (d IndicatorData) CountryAlpha2() string {
+	if c, ok := LookupCountry(d.CountryISO3Code); ok && len(d.CountryISO3Code) == 3 {
+		return c.Alpha2
+	}
+	return d.Country.ID
+}
+
 // IndicatorInfo contains basic indicator information
 type IndicatorInfo struct {
 	ID    string `json:"id"`
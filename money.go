@@ -0,0 +1,434 @@
+package ppp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an exact amount of money in integer minor units of a currency
+// Example: Money{Amount: 115500, Currency: "TRY"} is 1155.00 TRY
+type Money struct {
+	Amount   int64  // minor units, e.g. cents
+	Currency string // ISO 4217 code
+}
+
+// RoundingMode controls how exact results are rounded to minor units
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, ties away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest minor unit, ties to even (banker's rounding)
+	RoundHalfEven
+	// RoundDown rounds toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// NewMoney creates an amount from minor units
+// Example: NewMoney(999, "USD") is $9.99
+func NewMoney(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: strings.ToUpper(currency)}
+}
+
+// MoneyFromFloat converts a float amount to Money, rounding half up to minor units
+// The float is read as its shortest decimal form, so 1154.99999999999 style
+// representation errors don't leak into the result
+func MoneyFromFloat(amount float64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if err := ValidateCurrencyCode(currency); err != nil {
+		return Money{}, err
+	}
+	
+	r, err := ratFromFloat(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	
+	return moneyFromRat(r.Mul(r, scaleRat(currencyDigits(currency))), currency, RoundHalfUp)
+}
+
+// decimalPattern matches plain decimal amounts; big.Rat alone would also
+// accept Go literals such as "0x10", "0b11" or "1_000"
+var decimalPattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// ParseMoney parses a decimal string such as "1155.00" exactly
+// Returns an error if the amount has more decimals than the currency allows
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if err := ValidateCurrencyCode(currency); err != nil {
+		return Money{}, err
+	}
+	
+	trimmed := strings.TrimSpace(amount)
+	r, ok := new(big.Rat).SetString(trimmed)
+	if !ok || !decimalPattern.MatchString(trimmed) {
+		return Money{}, NewPPPError(
+			ErrCodeInvalidInput,
+			"invalid decimal amount",
+			ErrInvalidAmount,
+		).WithContext("amount", amount)
+	}
+	
+	r.Mul(r, scaleRat(currencyDigits(currency)))
+	if !r.IsInt() {
+		return Money{}, NewPPPError(
+			ErrCodeInvalidInput,
+			"amount has more decimals than the currency allows",
+			ErrInvalidAmount,
+		).WithContext("amount", amount).WithContext("currency", currency)
+	}
+	
+	return moneyFromRat(r, currency, RoundHalfUp)
+}
+
+// Digits returns the number of minor unit digits of the currency
+func (m Money) Digits() int {
+	return currencyDigits(m.Currency)
+}
+
+// Float64 returns the amount in major units as a float
+// Use only for display or interop with float APIs
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(m.Digits())
+}
+
+// Decimal returns the amount in major units as an exact decimal string
+// Example: "1155.00"
+func (m Money) Decimal() string {
+	digits := m.Digits()
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+	
+	// Work on the absolute value as uint64 so math.MinInt64 is safe
+	abs := uint64(amount)
+	if amount < 0 {
+		abs = uint64(-(amount + 1)) + 1
+	}
+	
+	s := strconv.FormatUint(abs, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// String returns the amount and currency, e.g. "1155.00 TRY"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Format formats the amount with the currency symbol, like FormatPrice
+func (m Money) Format() string {
+	return FormatPrice(m.Float64(), m.Currency)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Neg returns the amount with the opposite sign
+// Returns an error for math.MinInt64 minor units, which has no positive counterpart
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, errOverflow(m)
+	}
+	return Money{Amount: -m.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, errOverflow(m)
+	}
+	
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	
+	diff := m.Amount - other.Amount
+	if (other.Amount < 0 && diff < m.Amount) || (other.Amount > 0 && diff > m.Amount) {
+		return Money{}, errOverflow(m)
+	}
+	
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by an integer quantity
+func (m Money) Mul(quantity int64) (Money, error) {
+	r := new(big.Rat).SetInt64(m.Amount)
+	r.Mul(r, new(big.Rat).SetInt64(quantity))
+	return moneyFromRat(r, m.Currency, RoundHalfUp)
+}
+
+// MulRatio returns m * num / den, rounded to minor units
+// Example: MulRatio(833, 1000, RoundHalfUp) applies a 16.7% discount exactly
+func (m Money) MulRatio(num, den int64, mode RoundingMode) (Money, error) {
+	if den == 0 {
+		return Money{}, NewPPPError(ErrCodeInvalidInput, "division by zero", ErrInvalidAmount)
+	}
+	r := new(big.Rat).SetInt64(m.Amount)
+	r.Mul(r, big.NewRat(num, den))
+	return moneyFromRat(r, m.Currency, mode)
+}
+
+// MulFloat returns m multiplied by a factor, rounded to minor units
+// The factor is read as its shortest decimal form, so 11.55 is exactly 11.55
+func (m Money) MulFloat(factor float64, mode RoundingMode) (Money, error) {
+	f, err := ratFromFloat(factor)
+	if err != nil {
+		return Money{}, err
+	}
+	r := new(big.Rat).SetInt64(m.Amount)
+	return moneyFromRat(r.Mul(r, f), m.Currency, mode)
+}
+
+// Convert converts m to another currency at rate (units of to per unit of m)
+// Handles currencies with different numbers of minor unit digits
+func (m Money) Convert(rate float64, to string, mode RoundingMode) (Money, error) {
+	to = strings.ToUpper(to)
+	if err := ValidateCurrencyCode(to); err != nil {
+		return Money{}, err
+	}
+	
+	f, err := ratFromFloat(rate)
+	if err != nil {
+		return Money{}, err
+	}
+	
+	r := new(big.Rat).SetInt64(m.Amount)
+	r.Mul(r, f)
+	r.Mul(r, scaleRat(currencyDigits(to)))
+	r.Quo(r, scaleRat(m.Digits()))
+	
+	return moneyFromRat(r, to, mode)
+}
+
+// Allocate splits m by ratios without losing or creating minor units
+// Leftover units go to the parts with the largest remainders
+// Example: $100 allocated 1:1:1 is $33.34, $33.33, $33.33
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, NewPPPError(ErrCodeInvalidInput, "no ratios provided", nil)
+	}
+	
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, NewPPPError(ErrCodeInvalidInput, "ratios cannot be negative", nil).
+				WithContext("ratio", ratio)
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, NewPPPError(ErrCodeInvalidInput, "ratios must not all be zero", nil)
+	}
+	
+	amount := big.NewInt(m.Amount)
+	parts := make([]Money, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	allocated := new(big.Int)
+	
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(ratio))
+		rem := new(big.Int)
+		share.QuoRem(share, big.NewInt(total), rem)
+		parts[i] = Money{Amount: share.Int64(), Currency: m.Currency}
+		remainders[i] = rem.Abs(rem)
+		allocated.Add(allocated, share)
+	}
+	
+	// Hand out the leftover units one at a time, largest remainder first
+	leftover := new(big.Int).Sub(amount, allocated).Int64()
+	step := int64(1)
+	if leftover < 0 {
+		step = -1
+		leftover = -leftover
+	}
+	
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	
+	for i := int64(0); i < leftover; i++ {
+		parts[order[i]].Amount += step
+	}
+	
+	return parts, nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, NewPPPError(ErrCodeInvalidInput, "number of parts must be positive", nil).
+			WithContext("parts", n)
+	}
+	
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// moneyJSON is the JSON form of Money
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string
+// Example: {"amount":"1155.00","currency":"TRY"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON decodes a decimal string amount (plain JSON numbers are accepted too)
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	
+	amount := string(raw.Amount)
+	if strings.HasPrefix(amount, `"`) {
+		if err := json.Unmarshal(raw.Amount, &amount); err != nil {
+			return err
+		}
+	}
+	
+	parsed, err := ParseMoney(amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	
+	*m = parsed
+	return nil
+}
+
+// sameCurrency returns ErrCurrencyMismatch unless both amounts share a currency
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return NewPPPError(
+			ErrCodeInvalidInput,
+			fmt.Sprintf("cannot combine %s and %s", m.Currency, other.Currency),
+			ErrCurrencyMismatch,
+		)
+	}
+	return nil
+}
+
+// errOverflow reports an amount that doesn't fit in int64 minor units
+func errOverflow(m Money) error {
+	return NewPPPError(
+		ErrCodeInvalidInput,
+		"amount is too large",
+		ErrInvalidAmount,
+	).WithContext("currency", m.Currency)
+}
+
+// ratFromFloat converts a float to a rational using its shortest decimal form
+func ratFromFloat(f float64) (*big.Rat, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, NewPPPError(
+			ErrCodeInvalidInput,
+			"amount must be a finite number",
+			ErrInvalidAmount,
+		).WithContext("amount", f)
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r, nil
+}
+
+// scaleRat returns 10^digits as a rational
+func scaleRat(digits int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil))
+}
+
+// moneyFromRat rounds an amount in minor units to an integer
+func moneyFromRat(r *big.Rat, currency string, mode RoundingMode) (Money, error) {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	
+	if rem.Sign() != 0 {
+		sign := int64(r.Sign())
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		half := twice.Cmp(r.Denom())
+		
+		away := false
+		switch mode {
+		case RoundHalfUp:
+			away = half >= 0
+		case RoundHalfEven:
+			away = half > 0 || (half == 0 && q.Bit(0) == 1)
+		case RoundUp:
+			away = true
+		case RoundDown:
+			away = false
+		}
+		
+		if away {
+			q.Add(q, big.NewInt(sign))
+		}
+	}
+	
+	if !q.IsInt64() {
+		return Money{}, errOverflow(Money{Currency: currency})
+	}
+	
+	return Money{Amount: q.Int64(), Currency: currency}, nil
+}
@@ -0,0 +1,191 @@
+package ppp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/ppptest"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	price, err := ppp.MoneyFromFloat(1154.9999999999998, "TRY")
+	if err != nil {
+		t.Fatalf("MoneyFromFloat failed: %v", err)
+	}
+	if price.Amount != 115500 || price.String() != "1155.00 TRY" {
+		t.Errorf("Expected 1155.00 TRY, got %s (%d)", price, price.Amount)
+	}
+
+	// 0.1 + 0.2 is exact in minor units
+	a, _ := ppp.ParseMoney("0.10", "USD")
+	b, _ := ppp.ParseMoney("0.20", "usd")
+	sum, err := a.Add(b)
+	if err != nil || sum.Decimal() != "0.30" {
+		t.Errorf("0.10 + 0.20 = %s, %v", sum, err)
+	}
+
+	if _, err := a.Add(ppp.NewMoney(1, "EUR")); !errors.Is(err, ppp.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := ppp.ParseMoney("1.005", "USD"); err == nil {
+		t.Error("Expected error for too many decimals")
+	}
+	for _, input := range []string{"1e3", "0x10", "0b11", "0o7", "1_000", "1/2", ".5", "5.", "--1", "Inf", "NaN"} {
+		if m, err := ppp.ParseMoney(input, "USD"); err == nil {
+			t.Errorf("ParseMoney(%q) = %s, expected an error", input, m)
+		}
+	}
+	for input, want := range map[string]int64{"-1.50": -150, "+2": 200, " 3.25 ": 325} {
+		if m, err := ppp.ParseMoney(input, "USD"); err != nil || m.Amount != want {
+			t.Errorf("ParseMoney(%q) = %d, %v, want %d", input, m.Amount, err, want)
+		}
+	}
+	var decoded ppp.Money
+	if err := json.Unmarshal([]byte(`{"amount":"0x10","currency":"USD"}`), &decoded); err == nil {
+		t.Errorf("Expected UnmarshalJSON to reject hex amounts, got %s", decoded)
+	}
+
+	if neg, err := ppp.NewMoney(150, "USD").Neg(); err != nil || neg.Amount != -150 {
+		t.Errorf("Neg() = %s, %v", neg, err)
+	}
+	if _, err := ppp.NewMoney(math.MinInt64, "USD").Neg(); err == nil {
+		t.Error("Expected Neg to fail for math.MinInt64")
+	}
+
+	tests := []struct {
+		money ppp.Money
+		want  string
+	}{
+		{ppp.NewMoney(-5, "USD"), "-0.05"},
+		{ppp.NewMoney(1234, "JPY"), "1234"},
+		{ppp.NewMoney(1234, "KWD"), "1.234"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("Decimal(%d %s) = %s, want %s", tt.money.Amount, tt.money.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyRounding(t *testing.T) {
+	tests := []struct {
+		amount int64
+		mode   ppp.RoundingMode
+		want   int64
+	}{
+		{25, ppp.RoundHalfUp, 3},
+		{25, ppp.RoundHalfEven, 2},
+		{35, ppp.RoundHalfEven, 4},
+		{-25, ppp.RoundHalfUp, -3},
+		{29, ppp.RoundDown, 2},
+		{21, ppp.RoundUp, 3},
+		{-21, ppp.RoundUp, -3},
+	}
+	for _, tt := range tests {
+		got, err := ppp.NewMoney(tt.amount, "USD").MulRatio(1, 10, tt.mode)
+		if err != nil || got.Amount != tt.want {
+			t.Errorf("%d / 10 mode %d = %d, want %d (%v)", tt.amount, tt.mode, got.Amount, tt.want, err)
+		}
+	}
+
+	// USD to JPY drops the minor units, JPY to USD adds them
+	yen, err := ppp.NewMoney(999, "USD").Convert(144.12, "JPY", ppp.RoundHalfUp)
+	if err != nil || yen.Amount != 1440 {
+		t.Errorf("9.99 USD = %v JPY, want 1440 (%v)", yen.Amount, err)
+	}
+	usd, err := ppp.NewMoney(1000, "JPY").Convert(0.00694, "USD", ppp.RoundHalfUp)
+	if err != nil || usd.Amount != 694 {
+		t.Errorf("1000 JPY = %v USD cents, want 694 (%v)", usd.Amount, err)
+	}
+
+	converted, err := ppp.ConvertPriceMoney(ppp.NewMoney(9999, "USD"), 11.55, "TRY")
+	if err != nil || converted.Decimal() != "1154.88" {
+		t.Errorf("ConvertPriceMoney = %s, want 1154.88 (%v)", converted, err)
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	parts, err := ppp.NewMoney(10000, "USD").Split(3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if parts[0].Amount != 3334 || parts[1].Amount != 3333 || parts[2].Amount != 3333 {
+		t.Errorf("Unexpected split %v", parts)
+	}
+
+	parts, err = ppp.NewMoney(-100, "USD").Allocate(1, 2)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if parts[0].Amount+parts[1].Amount != -100 || parts[1].Amount != -67 {
+		t.Errorf("Unexpected allocation %v", parts)
+	}
+
+	if _, err := ppp.NewMoney(100, "USD").Allocate(0, 0); err == nil {
+		t.Error("Expected error for zero ratios")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(ppp.NewMoney(115500, "TRY"))
+	if err != nil || string(data) != `{"amount":"1155.00","currency":"TRY"}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+
+	var m ppp.Money
+	if err := json.Unmarshal([]byte(`{"amount":"19.99","currency":"EUR"}`), &m); err != nil || m != ppp.NewMoney(1999, "EUR") {
+		t.Errorf("Unmarshal string = %+v, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":5,"currency":"JPY"}`), &m); err != nil || m != ppp.NewMoney(5, "JPY") {
+		t.Errorf("Unmarshal number = %+v, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"0.5","currency":"JPY"}`), &m); err == nil {
+		t.Error("Expected error for fractional yen")
+	}
+}
+
+func TestMoneyAPIs(t *testing.T) {
+	client := ppptest.NewClient(t)
+	ctx := context.Background()
+
+	rec, err := client.RecommendMoney(ctx, ppp.NewMoney(9999, "USD"), "TR")
+	if err != nil {
+		t.Fatalf("RecommendMoney failed: %v", err)
+	}
+	if rec.Recommended.String() != "1154.88 TRY" || rec.MarketPrice.String() != "4046.60 TRY" {
+		t.Errorf("Unexpected recommendation %s / %s", rec.Recommended, rec.MarketPrice)
+	}
+
+	saas, err := ppp.NewRecommendationEngine(client).RecommendSaaSMoney(ctx, ppp.NewMoney(2999, "USD"), "TR")
+	if err != nil {
+		t.Fatalf("RecommendSaaSMoney failed: %v", err)
+	}
+	total, _ := saas.Annual.Add(saas.AnnualSavings)
+	if yearly, _ := saas.Monthly.Mul(12); total != yearly {
+		t.Errorf("Annual %s + savings %s != 12 x %s", saas.Annual, saas.AnnualSavings, saas.Monthly)
+	}
+
+	basket, err := ppp.CalculateMarketBasketMoney(ctx, client, map[string]ppp.Money{
+		"coffee": ppp.NewMoney(500, "USD"),
+		"lunch":  ppp.NewMoney(1500, "USD"),
+	}, "TR")
+	if err != nil {
+		t.Fatalf("CalculateMarketBasketMoney failed: %v", err)
+	}
+	// 5.00 * 11.55 / 40.47 = 1.427...
+	if basket["coffee"].String() != "1.43 USD" || basket["lunch"].String() != "4.28 USD" {
+		t.Errorf("Unexpected basket %v", basket)
+	}
+
+	_, err = ppp.CalculateMarketBasketMoney(ctx, client, map[string]ppp.Money{
+		"a": ppp.NewMoney(500, "USD"),
+		"b": ppp.NewMoney(500, "EUR"),
+	}, "TR")
+	if !errors.Is(err, ppp.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
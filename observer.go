@@ -0,0 +1,225 @@
+package ppp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Upstream names reported in request events
+const (
+	UpstreamWorldBank = "worldbank"
+	UpstreamCurrency  = "currency"
+)
+
+// RequestEvent describes one upstream HTTP request attempt
+type RequestEvent struct {
+	Upstream   string // UpstreamWorldBank or UpstreamCurrency
+	Method     string
+	URL        string
+	Host       string
+	StatusCode int // 0 when no response was received
+	Duration   time.Duration
+	Err        error
+}
+
+// CacheEvent describes one cache lookup
+type CacheEvent struct {
+	Family string // cache family: ppp, rate, countries or indicators
+	Key    string
+	Hit    bool
+}
+
+// Observer receives events for every upstream request and cache lookup
+// Implementations must be safe for concurrent use
+type Observer interface {
+	ObserveRequest(ctx context.Context, event RequestEvent)
+	ObserveCache(ctx context.Context, event CacheEvent)
+}
+
+// Tracer starts a span around every upstream request
+// NewSlogTracer is a built-in adapter; adapters for tracing libraries such
+// as OpenTelemetry implement this
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End(err error)
+}
+
+// WithObserver adds an observer for upstream requests and cache lookups
+// Can be given several times; observers are called in order
+func WithObserver(observer Observer) Option {
+	return func(c *Client) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
+// WithTracer sets the tracer used to create spans for upstream requests
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// observeCache reports a cache lookup to the observers
+func (c *Client) observeCache(ctx context.Context, family, key string, hit bool) {
+	for _, observer := range c.observers {
+		observer.ObserveCache(ctx, CacheEvent{Family: family, Key: key, Hit: hit})
+	}
+}
+
+// observedTransport reports every request to the client's observers and tracer
+type observedTransport struct {
+	upstream  string
+	observers []Observer
+	tracer    Tracer
+	base      http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	
+	var span Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.Start(ctx, "ppp."+t.upstream+" "+req.Method)
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.url", req.URL.String())
+		span.SetAttribute("net.peer.name", req.URL.Host)
+		req = req.WithContext(ctx)
+	}
+	
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	
+	event := RequestEvent{
+		Upstream: t.upstream,
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Host:     req.URL.Host,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	
+	if span != nil {
+		if event.StatusCode != 0 {
+			span.SetAttribute("http.status_code", event.StatusCode)
+		}
+		span.End(err)
+	}
+	
+	for _, observer := range t.observers {
+		observer.ObserveRequest(ctx, event)
+	}
+	
+	return resp, err
+}
+
+// slogObserver logs events with log/slog
+type slogObserver struct {
+	logger *slog.Logger
+}
+
+// NewSlogObserver creates an observer that logs to logger
+// Successful requests and cache lookups are logged at debug level;
+// failed requests and error responses at warn level
+func NewSlogObserver(logger *slog.Logger) Observer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogObserver{logger: logger}
+}
+
+// ObserveRequest implements Observer
+func (o *slogObserver) ObserveRequest(ctx context.Context, event RequestEvent) {
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("upstream", event.Upstream),
+		slog.String("method", event.Method),
+		slog.String("url", event.URL),
+		slog.Int("status", event.StatusCode),
+		slog.Duration("duration", event.Duration),
+	}
+	
+	if event.Err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	} else if event.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	
+	o.logger.LogAttrs(ctx, level, "ppp upstream request", attrs...)
+}
+
+// ObserveCache implements Observer
+func (o *slogObserver) ObserveCache(ctx context.Context, event CacheEvent) {
+	o.logger.LogAttrs(ctx, slog.LevelDebug, "ppp cache lookup",
+		slog.String("family", event.Family),
+		slog.String("key", event.Key),
+		slog.Bool("hit", event.Hit),
+	)
+}
+
+// slogTracer records spans as log/slog records
+type slogTracer struct {
+	logger *slog.Logger
+}
+
+// NewSlogTracer creates a tracer that logs every span when it ends, with its
+// duration and attributes. Spans ending with an error are logged at warn
+// level, the others at debug level
+func NewSlogTracer(logger *slog.Logger) Tracer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogTracer{logger: logger}
+}
+
+// Start implements Tracer
+func (t *slogTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, &slogSpan{logger: t.logger, ctx: ctx, name: name, start: time.Now()}
+}
+
+// slogSpan is a span logged by slogTracer
+type slogSpan struct {
+	logger *slog.Logger
+	ctx    context.Context
+	name   string
+	start  time.Time
+	
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// SetAttribute implements Span
+func (s *slogSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, slog.Any(key, value))
+}
+
+// End implements Span
+func (s *slogSpan) End(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	level := slog.LevelDebug
+	attrs := append([]slog.Attr{
+		slog.String("span", s.name),
+		slog.Duration("duration", time.Since(s.start)),
+	}, s.attrs...)
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	s.logger.LogAttrs(s.ctx, level, "ppp span", attrs...)
+}
@@ -0,0 +1,124 @@
+package ppp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{name: name, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordingSpan) End(err error)                              { s.ended = true }
+
+func TestObservers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "ZZ") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[{"page":1},[{"country":{"id":"TR","value":"Turkiye"},"date":"2024","value":11.55}]]`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	metrics := NewMetricsObserver(0.5, 1)
+	tracer := &recordingTracer{}
+	client := NewClient(
+		WithWorldBankURL(server.URL),
+		WithObserver(metrics),
+		WithObserver(NewSlogObserver(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))),
+		WithTracer(tracer),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPPP(ctx, "TR"); err != nil {
+			t.Fatalf("GetPPP failed: %v", err)
+		}
+	}
+	client.GetPPP(ctx, "ZZ")
+
+	var out bytes.Buffer
+	if _, err := metrics.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	for _, want := range []string{
+		`ppp_upstream_requests_total{upstream="worldbank",status="200"} 1`,
+		`ppp_upstream_requests_total{upstream="worldbank",status="404"} 1`,
+		`ppp_upstream_request_duration_seconds_bucket{upstream="worldbank",le="0.5"} 2`,
+		`ppp_upstream_request_duration_seconds_bucket{upstream="worldbank",le="+Inf"} 2`,
+		`ppp_upstream_request_duration_seconds_count{upstream="worldbank"} 2`,
+		`ppp_cache_lookups_total{family="ppp",result="hit"} 1`,
+		`ppp_cache_lookups_total{family="ppp",result="miss"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Metrics missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if !strings.Contains(logs.String(), "level=DEBUG msg=\"ppp upstream request\" upstream=worldbank") ||
+		!strings.Contains(logs.String(), "level=WARN") ||
+		!strings.Contains(logs.String(), "msg=\"ppp cache lookup\" family=ppp key=ppp:TR hit=true") {
+		t.Errorf("Unexpected logs:\n%s", logs.String())
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "ppp.worldbank GET" || !span.ended || span.attrs["http.status_code"] != 200 {
+		t.Errorf("Unexpected span %+v", span)
+	}
+}
+
+func TestSlogTracer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"page":1},[{"country":{"id":"TR","value":"Turkiye"},"date":"2024","value":11.55}]]`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(WithWorldBankURL(server.URL), WithTracer(NewSlogTracer(logger)))
+	if _, err := client.GetPPP(context.Background(), "TR"); err != nil {
+		t.Fatalf("GetPPP failed: %v", err)
+	}
+
+	out := logs.String()
+	for _, want := range []string{`level=DEBUG msg="ppp span" span="ppp.worldbank GET" duration=`, "http.method=GET", "http.status_code=200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in span log:\n%s", want, out)
+		}
+	}
+
+	logs.Reset()
+	_, span := NewSlogTracer(logger).Start(context.Background(), "failing")
+	span.End(fmt.Errorf("boom"))
+	if !strings.Contains(logs.String(), `level=WARN msg="ppp span" span=failing`) || !strings.Contains(logs.String(), "error=boom") {
+		t.Errorf("Expected a warning for a failed span, got:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,320 @@
+package ppp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ambiguousSymbolDefaults resolves shared symbols when the locale doesn't
+// (CLDR English uses "$" for USD, "¥" for JPY and "£" for GBP)
+var ambiguousSymbolDefaults = map[string]string{
+	"$":  "USD",
+	"¥":  "JPY",
+	"£":  "GBP",
+	"C$": "CAD",
+}
+
+// minusSigns are the characters accepted as a minus sign
+const minusSigns = "-−‐–"
+
+// symbolEntry maps a currency symbol to the currencies using it
+type symbolEntry struct {
+	symbol     string
+	currencies []Currency
+}
+
+var (
+	symbolsOnce sync.Once
+	symbols     []symbolEntry
+)
+
+// loadSymbols indexes registry symbols, longest first so "R$" wins over "R"
+func loadSymbols() []symbolEntry {
+	symbolsOnce.Do(func() {
+		bySymbol := make(map[string][]Currency)
+		for _, c := range Currencies() {
+			for _, symbol := range []string{c.Symbol, c.NarrowSymbol} {
+				if symbol == "" || symbol == c.Code {
+					continue
+				}
+				list := bySymbol[symbol]
+				if len(list) == 0 || list[len(list)-1].Code != c.Code {
+					bySymbol[symbol] = append(list, c)
+				}
+			}
+		}
+		
+		for symbol, list := range bySymbol {
+			symbols = append(symbols, symbolEntry{symbol: symbol, currencies: list})
+		}
+		sort.Slice(symbols, func(i, j int) bool {
+			if len(symbols[i].symbol) != len(symbols[j].symbol) {
+				return len(symbols[i].symbol) > len(symbols[j].symbol)
+			}
+			return symbols[i].symbol < symbols[j].symbol
+		})
+	})
+	return symbols
+}
+
+// ParsePrice parses a formatted price such as "₺1.149,00", "R$ 49,90" or "(USD 12.50)"
+// The currency comes from an ISO code or symbol in s, or else from the locale's
+// region. Separators follow the locale; with an empty locale they are inferred.
+// Negative amounts may use any minus sign or accounting parentheses
+// Example: ParsePrice("€1.155,00", "de-DE") returns 1155.00 EUR
+func ParsePrice(s, locale string) (Money, error) {
+	input := s
+	s = strings.TrimSpace(normalizeSpaces(s))
+	
+	var f *localeFormat
+	region := ""
+	if strings.TrimSpace(locale) != "" {
+		f, region = resolveLocale(locale)
+	}
+	
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	
+	currency, rest, perr := extractCurrency(s, region)
+	if perr != nil {
+		return Money{}, perr.WithContext("price", input)
+	}
+	
+	// Signs may sit on either side of the symbol
+	rest = strings.TrimSpace(rest)
+	if i := strings.IndexAny(rest, minusSigns); i >= 0 {
+		negative = !negative
+		_, size := utf8.DecodeRuneInString(rest[i:])
+		rest = rest[:i] + rest[i+size:]
+	} else if strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+	}
+	
+	number, ok := normalizeNumber(strings.TrimSpace(rest), f)
+	if !ok {
+		return Money{}, NewPPPError(
+			ErrCodeInvalidInput,
+			"invalid price format",
+			ErrInvalidAmount,
+		).WithContext("price", input)
+	}
+	
+	m, err := ParseMoney(number, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	if negative {
+		return m.Neg()
+	}
+	return m, nil
+}
+
+// normalizeSpaces turns every kind of space, including no-break spaces, into a plain space
+func normalizeSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// extractCurrency finds the currency code or symbol in s and removes it
+func extractCurrency(s, region string) (string, string, *PPPError) {
+	// ISO codes first: any three-letter word that is a known currency
+	for start := 0; start < len(s); {
+		r, size := utf8.DecodeRuneInString(s[start:])
+		if !unicode.IsLetter(r) {
+			start += size
+			continue
+		}
+		end := start
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if !unicode.IsLetter(r) {
+				break
+			}
+			end += size
+		}
+		if word := s[start:end]; len(word) == 3 {
+			if c, ok := LookupCurrency(word); ok {
+				return c.Code, s[:start] + s[end:], nil
+			}
+		}
+		start = end
+	}
+	
+	for _, entry := range loadSymbols() {
+		i := indexSymbol(s, entry.symbol)
+		if i < 0 {
+			continue
+		}
+		
+		code, err := chooseCurrency(entry, region)
+		if err != nil {
+			return "", "", err
+		}
+		return code, s[:i] + s[i+len(entry.symbol):], nil
+	}
+	
+	// No currency in the string, use the locale region's
+	if region != "" {
+		if list := CurrenciesForCountry(region); len(list) == 1 {
+			return list[0].Code, s, nil
+		}
+	}
+	
+	return "", "", NewPPPError(
+		ErrCodeInvalidInput,
+		"price has no recognizable currency",
+		ErrInvalidCurrency,
+	)
+}
+
+// indexSymbol finds symbol in s, not as part of a longer word
+// Example: "R" matches "R 100" but not "Rp 100"
+func indexSymbol(s, symbol string) int {
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], symbol)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		
+		first, _ := utf8.DecodeRuneInString(symbol)
+		last, _ := utf8.DecodeLastRuneInString(symbol)
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[i+len(symbol):])
+		
+		if !(unicode.IsLetter(first) && unicode.IsLetter(before)) &&
+			!(unicode.IsLetter(last) && unicode.IsLetter(after)) {
+			return i
+		}
+		offset = i + len(symbol)
+	}
+	return -1
+}
+
+// chooseCurrency resolves a symbol shared by several currencies
+func chooseCurrency(entry symbolEntry, region string) (string, *PPPError) {
+	if len(entry.currencies) == 1 {
+		return entry.currencies[0].Code, nil
+	}
+	
+	// Prefer the region's own currency, e.g. "$" in es-MX is MXN
+	for _, c := range entry.currencies {
+		for _, country := range c.Countries {
+			if country == region {
+				return c.Code, nil
+			}
+		}
+	}
+	
+	// Then a currency whose unambiguous symbol this is
+	var exact []string
+	for _, c := range entry.currencies {
+		if c.Symbol == entry.symbol {
+			exact = append(exact, c.Code)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	
+	if code, ok := ambiguousSymbolDefaults[entry.symbol]; ok {
+		return code, nil
+	}
+	
+	codes := make([]string, len(entry.currencies))
+	for i, c := range entry.currencies {
+		codes[i] = c.Code
+	}
+	return "", NewPPPError(
+		ErrCodeInvalidInput,
+		"currency symbol is ambiguous, pass a locale or use an ISO code",
+		ErrInvalidCurrency,
+	).WithContext("symbol", entry.symbol).WithContext("currencies", codes)
+}
+
+// normalizeNumber converts a localized number to a plain decimal string
+// f is nil when separators should be inferred
+func normalizeNumber(s string, f *localeFormat) (string, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return "", false
+	}
+	
+	decimal, group := ".", ","
+	if f != nil {
+		decimal, group = f.Decimal, f.Group
+		if strings.TrimSpace(normalizeSpaces(group)) == "" {
+			// Spaces were already removed
+			group = ""
+		}
+		if group == "’" {
+			s = strings.ReplaceAll(s, "'", group)
+		}
+	} else {
+		decimal, group = inferSeparators(s)
+	}
+	
+	integer, fraction, hasFraction := strings.Cut(s, decimal)
+	if strings.Contains(fraction, decimal) || (group != "" && strings.Contains(fraction, group)) {
+		return "", false
+	}
+	
+	if group != "" {
+		groups := strings.Split(integer, group)
+		for i, g := range groups {
+			// Every group after the first has two or three digits (lakh grouping uses two)
+			if g == "" || (i > 0 && len(g) != 2 && len(g) != 3) {
+				return "", false
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+	
+	if integer == "" || !isDigits(integer) || (hasFraction && (fraction == "" || !isDigits(fraction))) {
+		return "", false
+	}
+	
+	if hasFraction {
+		return integer + "." + fraction, true
+	}
+	return integer, true
+}
+
+// inferSeparators guesses the decimal and grouping separators of a number
+// The last of "." and "," is the decimal separator, unless it is the only
+// separator and is followed by exactly three digits, as in "1,980" or "1.149"
+func inferSeparators(s string) (decimal, group string) {
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+	
+	switch {
+	case lastDot < 0 && lastComma < 0:
+		return ".", ","
+	case lastDot >= 0 && lastComma >= 0:
+		if lastDot > lastComma {
+			return ".", ","
+		}
+		return ",", "."
+	}
+	
+	sep, last := ".", lastDot
+	other := ","
+	if lastComma >= 0 {
+		sep, last, other = ",", lastComma, "."
+	}
+	
+	if strings.Count(s, sep) > 1 || len(s)-last-1 == 3 {
+		return other, sep
+	}
+	return sep, other
+}
@@ -0,0 +1,80 @@
+package ppp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		input  string
+		locale string
+		want   Money
+	}{
+		{"₺1.149,00", "tr-TR", NewMoney(114900, "TRY")},
+		{"₺1.149,00", "", NewMoney(114900, "TRY")},
+		{"R$ 49,90", "pt-BR", NewMoney(4990, "BRL")},
+		{"R$ 49,90", "", NewMoney(4990, "BRL")},
+		{"¥1,980", "ja-JP", NewMoney(1980, "JPY")},
+		{"¥1,980", "zh-CN", NewMoney(198000, "CNY")},
+		{"¥1,980", "", NewMoney(1980, "JPY")},
+		{"1.155,00 €", "de-DE", NewMoney(115500, "EUR")},
+		{"1 155,00 €", "fr-FR", NewMoney(115500, "EUR")},
+		{"1155,00", "bg-BG", NewMoney(115500, "EUR")},
+		{"₹12,34,567.50", "en-IN", NewMoney(123456750, "INR")},
+		{"CHF-1’155.00", "de-CH", NewMoney(-115500, "CHF")},
+		{"CHF 1'155.00", "de-CH", NewMoney(115500, "CHF")},
+		{"(USD 12.50)", "", NewMoney(-1250, "USD")},
+		{"-$1,155.00", "en", NewMoney(-115500, "USD")},
+		{"$-5", "", NewMoney(-500, "USD")},
+		{"−1 155,00 kr", "sv-SE", NewMoney(-115500, "SEK")},
+		{"$1,155.00", "es-MX", NewMoney(115500, "MXN")},
+		{"C$1,155.00", "en-US", NewMoney(115500, "CAD")},
+		{"Rp150.000", "id-ID", NewMoney(15000000, "IDR")},
+		{"eur 10", "", NewMoney(1000, "EUR")},
+		{"1.149,00", "tr-TR", NewMoney(114900, "TRY")},
+		{"KD 1,155.000", "en", NewMoney(1155000, "KWD")},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePrice(tt.input, tt.locale)
+		if err != nil {
+			t.Errorf("ParsePrice(%q, %q) failed: %v", tt.input, tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePrice(%q, %q) = %s, want %s", tt.input, tt.locale, got, tt.want)
+		}
+	}
+
+	// Formatting and parsing round-trip
+	for _, locale := range []string{"en", "de", "fr", "en-IN", "de-CH", "pt-BR", "es", "sv"} {
+		formatted := FormatPriceLocale(-1234567.89, "EUR", locale)
+		got, err := ParsePrice(formatted, locale)
+		if err != nil || got != NewMoney(-123456789, "EUR") {
+			t.Errorf("Round-trip %q in %s = %s, %v", formatted, locale, got, err)
+		}
+	}
+
+	failures := []struct {
+		input  string
+		locale string
+	}{
+		{"$1,155.00", "de-DE"},
+		{"kr 100", ""},
+		{"100", ""},
+		{"€1.2.3", "en"},
+		{"€12,5,00", "en"},
+		{"¥19.80", "ja"},
+		{"€", "de"},
+	}
+	for _, tt := range failures {
+		if got, err := ParsePrice(tt.input, tt.locale); err == nil {
+			t.Errorf("ParsePrice(%q, %q) = %s, expected error", tt.input, tt.locale, got)
+		}
+	}
+
+	if _, err := ParsePrice("kr 100", ""); !errors.Is(err, ErrInvalidCurrency) {
+		t.Errorf("Expected ErrInvalidCurrency for ambiguous symbol, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package ppp
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -32,7 +33,8 @@ func RecommendPrice(price float64, fromCurrency, toCountry string) (float64, err
 		return 0, err
 	}
 	
-	if err := ValidateCountryCode(toCountry); err != nil {
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
 		return 0, err
 	}
 	
@@ -56,7 +58,8 @@ func RecommendPrice(price float64, fromCurrency, toCountry string) (float64, err
 // GetFactor returns the PPP factor for a country
 // Returns (factor, error)
 func GetFactor(countryCode string) (float64, error) {
-	if err := ValidateCountryCode(countryCode); err != nil {
+	countryCode, err := NormalizeCountryCode(countryCode)
+	if err != nil {
 		return 0, err
 	}
 	
@@ -93,6 +96,25 @@ func ConvertPrice(price float64, pppFactor float64) (float64, error) {
 	return price * pppFactor, nil
 }
 
+// ConvertPriceMoney converts a price using PPP factor into toCurrency
+// The result is exact and rounded half up to the currency's minor units
+// Example: ConvertPriceMoney(NewMoney(10000, "USD"), 11.55, "TRY") returns 1155.00 TRY
+func ConvertPriceMoney(price Money, pppFactor float64, toCurrency string) (Money, error) {
+	if err := ValidateAmount(price.Float64()); err != nil {
+		return Money{}, err
+	}
+	
+	if pppFactor <= 0 {
+		return Money{}, NewPPPError(
+			ErrCodeInvalidInput,
+			"PPP factor must be positive",
+			nil,
+		).WithContext("ppp_factor", pppFactor)
+	}
+	
+	return price.Convert(pppFactor, toCurrency, RoundHalfUp)
+}
+
 // GetRate returns exchange rate between two currencies
 // Returns (rate, error)
 func GetRate(from, to string) (float64, error) {
@@ -131,7 +153,8 @@ func QuickRecommend(price float64, fromCurrency, toCountry string) (*PriceRecomm
 		return nil, err
 	}
 	
-	if err := ValidateCountryCode(toCountry); err != nil {
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
 		return nil, err
 	}
 	
@@ -197,10 +220,13 @@ func FindIndicators(search string) ([]Indicator, error) {
 }
 
 // GetCountryCode finds ISO2 code by country name (case insensitive)
+// Understands native and localized names, abbreviations and small typos.
+// Other names are matched against the country list, which includes World Bank
+// aggregates such as "Euro area" when the default client uses WithLiveCountries
 // Example: GetCountryCode("turkey") returns "TR", nil
-// Example: GetCountryCode("United States") returns "US", nil
+// Example: GetCountryCode("Deutschland") returns "DE", nil
 func GetCountryCode(countryName string) (string, error) {
-	if countryName == "" {
+	if strings.TrimSpace(countryName) == "" {
 		return "", NewPPPError(
 			ErrCodeInvalidInput,
 			"country name cannot be empty",
@@ -208,6 +234,11 @@ func GetCountryCode(countryName string) (string, error) {
 		)
 	}
 	
+	matches := SearchCountries(countryName, 5)
+	if code, ok := confidentMatch(matches); ok {
+		return code, nil
+	}
+	
 	// Get all countries
 	countries, err := ListCountries()
 	if err != nil {
@@ -225,23 +256,31 @@ func GetCountryCode(countryName string) (string, error) {
 	}
 	
 	// Then try contains match
-	var matches []Country
+	var contains []Country
 	for _, country := range countries {
 		if strings.Contains(strings.ToLower(country.Name), searchName) {
-			matches = append(matches, country)
+			contains = append(contains, country)
 		}
 	}
 	
 	// If exactly one match, return it
-	if len(matches) == 1 {
-		return matches[0].ISO2Code, nil
+	if len(contains) == 1 {
+		return contains[0].ISO2Code, nil
 	}
 	
-	// If multiple matches, return error with suggestions
-	if len(matches) > 1 {
+	// If multiple matches, return error with ranked suggestions
+	if len(matches) > 0 || len(contains) > 1 {
 		var suggestions []string
 		for _, match := range matches {
-			suggestions = append(suggestions, fmt.Sprintf("%s (%s)", match.Name, match.ISO2Code))
+			suggestions = append(suggestions, fmt.Sprintf("%s (%s)", match.Name, match.Code))
+		}
+		if len(suggestions) == 0 {
+			sort.Slice(contains, func(i, j int) bool {
+				return contains[i].Name < contains[j].Name
+			})
+			for _, match := range contains {
+				suggestions = append(suggestions, fmt.Sprintf("%s (%s)", match.Name, match.ISO2Code))
+			}
 		}
 		return "", NewPPPError(
 			ErrCodeInvalidInput,
@@ -258,6 +297,23 @@ func GetCountryCode(countryName string) (string, error) {
 	)
 }
 
+// confidentMatch returns the top search result when it is exact or clearly
+// ahead of the runner-up
+func confidentMatch(matches []CountryMatch) (string, bool) {
+	if len(matches) == 0 {
+		return "", false
+	}
+	
+	top := matches[0]
+	if top.Score >= 0.97 {
+		return top.Code, true
+	}
+	if top.Score >= 0.65 && (len(matches) == 1 || top.Score-matches[1].Score >= 0.1) {
+		return top.Code, true
+	}
+	return "", false
+}
+
 // BatchRecommend calculates recommended prices for multiple countries
 // Returns (countryPrices, error)
 func BatchRecommend(price float64, fromCurrency string, toCountries []string) (map[string]float64, error) {
@@ -278,11 +334,14 @@ func BatchRecommend(price float64, fromCurrency string, toCountries []string) (m
 		)
 	}
 	
-	// Validate all country codes
-	for _, country := range toCountries {
-		if err := ValidateCountryCode(country); err != nil {
+	// Validate all country codes; results stay keyed by the caller's codes
+	normalized := make([]string, len(toCountries))
+	for i, country := range toCountries {
+		code, err := NormalizeCountryCode(country)
+		if err != nil {
 			return nil, err
 		}
+		normalized[i] = code
 	}
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -291,8 +350,8 @@ func BatchRecommend(price float64, fromCurrency string, toCountries []string) (m
 	results := make(map[string]float64)
 	var lastError error
 	
-	for _, country := range toCountries {
-		rec, err := defaultClient.Recommend(ctx, price, fromCurrency, country)
+	for i, country := range toCountries {
+		rec, err := defaultClient.Recommend(ctx, price, fromCurrency, normalized[i])
 		if err != nil {
 			// Store error but continue with other countries
 			lastError = err
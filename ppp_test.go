@@ -1,6 +1,13 @@
 package ppp
 
 import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -15,7 +22,9 @@ func TestValidateCountryCode(t *testing.T) {
 		{"Valid code US", "US", false},
 		{"Too short", "T", true},
 		{"Too long", "TUR", true},
-		{"Lowercase", "tr", true},
+		{"Lowercase", "tr", false},
+		{"Alias", "UK", false},
+		{"Unknown", "ZZ", true},
 		{"Numbers", "T1", true},
 		{"Empty", "", true},
 	}
@@ -43,6 +52,7 @@ func TestValidateCurrencyCode(t *testing.T) {
 		{"Lowercase", "usd", true},
 		{"Numbers", "US1", true},
 		{"Empty", "", true},
+		{"Not ISO 4217", "ABC", true},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +76,9 @@ func TestValidateAmount(t *testing.T) {
 		{"Zero amount", 0, true},
 		{"Negative amount", -10, true},
 		{"Very large amount", 1e16, true},
+		{"NaN", math.NaN(), true},
+		{"Infinity", math.Inf(1), true},
+		{"Negative infinity", math.Inf(-1), true},
 	}
 
 	for _, tt := range tests {
@@ -120,6 +133,9 @@ func TestRoundPrice(t *testing.T) {
 		{"JPY no decimals", 1234.56, "JPY", 1235},
 		{"KRW no decimals", 50000.99, "KRW", 50001},
 		{"TRY with decimals", 123.456, "TRY", 123.46},
+		{"KWD three decimals", 1.23456, "KWD", 1.235},
+		{"IDR no cash decimals", 15000.5, "IDR", 15001},
+		{"Unknown currency", 1.234, "XXX", 1.23},
 	}
 
 	for _, tt := range tests {
@@ -145,6 +161,9 @@ func TestFormatPrice(t *testing.T) {
 		{"TRY format", 1234.56, "TRY", "₺1234.56"},
 		{"JPY format", 1000, "JPY", "¥1000"},
 		{"Unknown currency", 100, "XXX", "XXX 100.00"},
+		{"KWD format", 12.5, "KWD", "KD12.500"},
+		{"IDR format", 150000, "IDR", "Rp150000"},
+		{"CHF format", 100, "CHF", "CHF100.00"},
 	}
 
 	for _, tt := range tests {
@@ -157,6 +176,116 @@ func TestFormatPrice(t *testing.T) {
 	}
 }
 
+func TestLookupCurrency(t *testing.T) {
+	kwd, ok := LookupCurrency("kwd")
+	if !ok || kwd.Numeric != 414 || kwd.Digits != 3 || kwd.Name != "Kuwaiti Dinar" {
+		t.Errorf("Unexpected KWD %+v", kwd)
+	}
+
+	idr, ok := LookupCurrency("IDR")
+	if !ok || idr.Digits != 2 || idr.CashDigits != 0 {
+		t.Errorf("Expected IDR with 2 digits and 0 cash digits, got %+v", idr)
+	}
+
+	cad, _ := LookupCurrency("CAD")
+	if cad.Symbol != "C$" || cad.NarrowSymbol != "$" {
+		t.Errorf("Unexpected CAD symbols %q %q", cad.Symbol, cad.NarrowSymbol)
+	}
+
+	if try, ok := LookupCurrencyNumeric(949); !ok || try.Code != "TRY" {
+		t.Errorf("Expected TRY for 949, got %+v", try)
+	}
+	if _, ok := LookupCurrency("XXX"); ok {
+		t.Error("Expected XXX to be absent")
+	}
+
+	var codes []string
+	for _, c := range CurrenciesForCountry("PA") {
+		codes = append(codes, c.Code)
+	}
+	if strings.Join(codes, ",") != "PAB,USD" {
+		t.Errorf("Expected PAB,USD for Panama, got %v", codes)
+	}
+	// Bulgaria adopted the euro on 2026-01-01
+	if bg := CurrenciesForCountry("BG"); len(bg) != 1 || bg[0].Code != "EUR" {
+		t.Errorf("Expected EUR for Bulgaria, got %+v", bg)
+	}
+
+	all := Currencies()
+	all[0].Countries[0] = "ZZ"
+	if again := Currencies(); again[0].Countries[0] == "ZZ" {
+		t.Error("Currencies must return copies")
+	}
+}
+
+func TestLookupCountry(t *testing.T) {
+	for _, code := range []string{"TR", "tr", "TUR", "tur", "792"} {
+		c, ok := LookupCountry(code)
+		if !ok || c.Alpha2 != "TR" || c.Alpha3 != "TUR" || c.Numeric != 792 {
+			t.Errorf("LookupCountry(%q) = %+v, %v", code, c, ok)
+		}
+	}
+
+	if c, ok := LookupCountry("020"); !ok || c.Alpha2 != "AD" {
+		t.Errorf("Expected Andorra for 020, got %+v", c)
+	}
+	if _, ok := LookupCountry("ZZ"); ok {
+		t.Error("Expected ZZ to be absent")
+	}
+	if n := len(ISOCountries()); n < 249 {
+		t.Errorf("Expected at least 249 countries, got %d", n)
+	}
+}
+
+func TestNormalizeCountryCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"TR", "TR", false},
+		{" tr ", "TR", false},
+		{"UK", "GB", false},
+		{"el", "GR", false},
+		{"DEU", "DE", false},
+		{"840", "US", false},
+		{"XK", "XK", false},
+		{"ZZ", "", true},
+		{"ZZZ", "", true},
+		{"999", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeCountryCode(tt.code)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeCountryCode(%q) = %q, %v; want %q", tt.code, got, err, tt.want)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidCountry) {
+			t.Errorf("Expected ErrInvalidCountry, got %v", err)
+		}
+	}
+
+	if alpha3, _ := CountryAlpha3("uk"); alpha3 != "GBR" {
+		t.Errorf("CountryAlpha3(uk) = %q, want GBR", alpha3)
+	}
+	if numeric, _ := CountryNumeric("TR"); numeric != 792 {
+		t.Errorf("CountryNumeric(TR) = %d, want 792", numeric)
+	}
+	if _, ok := CountryNumeric("XK"); ok {
+		t.Error("Kosovo has no numeric code")
+	}
+
+	dp := IndicatorData{Country: CountryInfo{ID: "T2"}, CountryISO3Code: "TUR"}
+	if got := dp.CountryAlpha2(); got != "TR" {
+		t.Errorf("CountryAlpha2 = %q, want TR", got)
+	}
+	dp.CountryISO3Code = "EUU"
+	if got := dp.CountryAlpha2(); got != "T2" {
+		t.Errorf("CountryAlpha2 = %q, want fallback T2", got)
+	}
+}
+
 func TestCache(t *testing.T) {
 	cache := NewCache(1*time.Minute, 2*time.Minute)
 
@@ -283,4 +412,134 @@ func TestPPPError(t *testing.T) {
 	if !IsNoDataError(err) {
 		t.Error("Expected IsNoDataError to return true")
 	}
-}
\ No newline at end of file
+}
+
+func TestCacheStatsAndInvalidation(t *testing.T) {
+	cache := NewCache(1*time.Minute, 2*time.Minute)
+
+	cache.SetPPP("TR", &PPPData{CountryCode: "TR", Factor: 11.55}, time.Minute)
+	cache.SetPPP("BR", &PPPData{CountryCode: "BR", Factor: 2.4}, time.Minute)
+	cache.SetExchangeRate("USD", "TRY", &ExchangeRate{From: "USD", To: "TRY", Rate: 40}, time.Minute)
+	cache.SetExchangeRate("EUR", "TRY", &ExchangeRate{From: "EUR", To: "TRY", Rate: 44}, time.Minute)
+	cache.SetExchangeRate("USD", "BRL", &ExchangeRate{From: "USD", To: "BRL", Rate: 5}, time.Minute)
+
+	cache.GetPPP("TR")
+	cache.GetPPP("DE")
+	cache.GetExchangeRate("USD", "TRY")
+
+	stats := cache.Stats()
+	if got := stats[CacheFamilyPPP]; got.Hits != 1 || got.Misses != 1 || got.Sets != 2 {
+		t.Errorf("ppp stats = %+v, want 1 hit, 1 miss, 2 sets", got)
+	}
+	if got := stats[CacheFamilyRate].HitRatio(); got != 1 {
+		t.Errorf("rate hit ratio = %v, want 1", got)
+	}
+
+	entries := cache.Entries()
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+	if entries[0].Key != "ppp:BR" || entries[0].Family != CacheFamilyPPP || entries[0].ExpiresAt.IsZero() {
+		t.Errorf("Unexpected first entry %+v", entries[0])
+	}
+
+	if n := cache.InvalidateCurrency("try"); n != 2 {
+		t.Errorf("InvalidateCurrency removed %d entries, want 2", n)
+	}
+	if n := cache.InvalidateCountry("TR"); n != 1 {
+		t.Errorf("InvalidateCountry removed %d entries, want 1", n)
+	}
+	if n := cache.InvalidatePrefix("rate:"); n != 1 {
+		t.Errorf("InvalidatePrefix removed %d entries, want 1", n)
+	}
+	if _, found := cache.GetPPP("BR"); !found {
+		t.Error("Expected BR to survive invalidation")
+	}
+
+	stats = cache.Stats()
+	if got := stats[CacheFamilyRate]; got.Invalidations != 3 || got.Evictions != 0 {
+		t.Errorf("rate stats = %+v, want 3 invalidations and no evictions", got)
+	}
+}
+
+func TestReleaseSchedule(t *testing.T) {
+	schedule := DefaultPPPRelease
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		data       *PPPData
+		latestYear int
+		wantTTL    time.Duration
+	}{
+		{"Before release", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil, 2022, 122 * day},
+		{"Stale data after release", time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), &PPPData{Year: 2022}, 2023, day},
+		{"Fresh data after release", time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), &PPPData{Year: 2023}, 2023, 356 * day},
+		{"Outside release window", time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), &PPPData{Year: 2022}, 2023, 273 * day},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.LatestYear(tt.now); got != tt.latestYear {
+				t.Errorf("LatestYear() = %d, want %d", got, tt.latestYear)
+			}
+			if got := schedule.TTL(tt.now, tt.data); got != tt.wantTTL {
+				t.Errorf("TTL() = %v, want %v", got, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestClientTTLs(t *testing.T) {
+	client := NewClient(WithRateTTL(5*time.Minute), WithCountriesTTL(time.Hour))
+	if got := client.ttl(CacheFamilyRate); got != 5*time.Minute {
+		t.Errorf("rate TTL = %v, want 5m", got)
+	}
+	if got := client.ttl(CacheFamilyIndicators); got != 24*time.Hour {
+		t.Errorf("indicators TTL = %v, want 24h", got)
+	}
+	if got := client.ttl(CacheFamilyPPP); got <= 0 || got > 366*24*time.Hour {
+		t.Errorf("PPP TTL = %v, want time until next release", got)
+	}
+
+	// WithCache applies to every family without its own option
+	client = NewClient(WithCache(10*time.Minute), WithPPPTTL(48*time.Hour))
+	if got := client.ttl(CacheFamilyRate); got != 10*time.Minute {
+		t.Errorf("rate TTL = %v, want 10m", got)
+	}
+	if got := client.ttl(CacheFamilyPPP); got != 48*time.Hour {
+		t.Errorf("PPP TTL = %v, want 48h", got)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithOffline(), WithWorldBankURL(server.URL), WithCurrencyURL(server.URL))
+	client.cache.SetPPP("TR", &PPPData{CountryCode: "TR", Factor: 11.55}, time.Hour)
+	client.cache.SetExchangeRate("USD", "TRY", &ExchangeRate{From: "USD", To: "TRY", Rate: 40.47}, time.Hour)
+	ctx := context.Background()
+
+	rec, err := client.Recommend(ctx, 100, "USD", "TR")
+	if err != nil {
+		t.Fatalf("Expected cached data to be served offline, got %v", err)
+	}
+	if rec.PPPFactor != 11.55 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+	if countries, err := client.GetCountries(ctx); err != nil || len(countries) == 0 {
+		t.Errorf("Expected the embedded country list offline, got %d countries, %v", len(countries), err)
+	}
+
+	if _, err := client.GetPPP(ctx, "DE"); !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected ErrOffline, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected no upstream requests offline, got %d", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Package ppptest provides fake World Bank and currency API servers seeded
+// from fixture files, and a transport that records real responses as fixtures
+package ppptest
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// datedPath matches the date of a dated currency API URL
+var datedPath = regexp.MustCompile(`@(\d{4}-\d{2}-\d{2})(/|$)`)
+
+//go:embed fixtures
+var embedded embed.FS
+
+// Fixtures is the built-in fixture set: a country list, PPP series for
+// 2014-2024 for about twenty countries, an indicator list and rate tables
+// for USD, EUR, GBP and TRY. Values are rounded samples of real data.
+var Fixtures fs.FS
+
+func init() {
+	Fixtures, _ = fs.Sub(embedded, "fixtures")
+}
+
+// Upstream is a pair of fake upstream servers
+type Upstream struct {
+	WorldBank *httptest.Server
+	Currency  *httptest.Server
+	requests  int64
+}
+
+// NewUpstream starts fake World Bank and currency servers serving fixtures
+// (Fixtures if nil). The servers are closed when the test finishes.
+func NewUpstream(t testing.TB, fixtures fs.FS) *Upstream {
+	t.Helper()
+	if fixtures == nil {
+		fixtures = Fixtures
+	}
+	
+	u := &Upstream{}
+	u.WorldBank = httptest.NewServer(u.count(NewWorldBankHandler(fixtures)))
+	u.Currency = httptest.NewServer(u.count(NewCurrencyHandler(fixtures)))
+	t.Cleanup(u.Close)
+	
+	return u
+}
+
+// count wraps a handler to count requests
+func (u *Upstream) count(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&u.requests, 1)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Requests returns how many requests both servers have received
+func (u *Upstream) Requests() int {
+	return int(atomic.LoadInt64(&u.requests))
+}
+
+// Options returns client options pointing at the fake servers
+func (u *Upstream) Options() []ppp.Option {
+	return []ppp.Option{
+		ppp.WithWorldBankURL(u.WorldBank.URL),
+		ppp.WithCurrencyURL(u.Currency.URL),
+	}
+}
+
+// Client returns a client using the fake servers
+// Additional options are applied after the server URLs
+func (u *Upstream) Client(opts ...ppp.Option) *ppp.Client {
+	return ppp.NewClient(append(u.Options(), opts...)...)
+}
+
+// Close shuts down both servers
+func (u *Upstream) Close() {
+	u.WorldBank.Close()
+	u.Currency.Close()
+}
+
+// NewClient starts fake servers with the built-in fixtures and returns a
+// client using them
+func NewClient(t testing.TB, opts ...ppp.Option) *ppp.Client {
+	t.Helper()
+	return NewUpstream(t, nil).Client(opts...)
+}
+
+// FixturePath maps an upstream request URL to its fixture file
+// Example: /v2/country/TR/indicator/PA.NUS.PPP -> worldbank/country/TR/indicator/PA.NUS.PPP.json
+// Example: /npm/@fawazahmed0/currency-api@latest/v1/currencies/usd.json -> currency/currencies/usd.json
+// Example: /npm/@fawazahmed0/currency-api@2023-03-01/v1/currencies/usd.json -> currency/2023-03-01/currencies/usd.json
+func FixturePath(u *url.URL) (string, bool) {
+	p := u.Path
+	
+	var fixture string
+	if i := strings.Index(p, "/currencies/"); i >= 0 {
+		// Dated snapshots live in their own directory
+		date := u.Query().Get("date")
+		if m := datedPath.FindStringSubmatch(p[:i]); m != nil {
+			date = m[1]
+		}
+		if date != "" {
+			fixture = "currency/" + date + p[i:]
+		} else {
+			fixture = "currency" + p[i:]
+		}
+	} else {
+		p = strings.TrimPrefix(p, "/v2")
+		if p == "" || p == "/" {
+			return "", false
+		}
+		fixture = "worldbank" + p + ".json"
+	}
+	
+	fixture = path.Clean(fixture)
+	if strings.Contains(fixture, "..") {
+		return "", false
+	}
+	
+	return fixture, true
+}
@@ -0,0 +1,110 @@
+package ppptest
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+func TestFakeUpstream(t *testing.T) {
+	upstream := NewUpstream(t, nil)
+	client := upstream.Client()
+	ctx := context.Background()
+
+	rec, err := client.Recommend(ctx, 100, "USD", "TR")
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if rec.PPPFactor != 11.55 || rec.ExchangeRate != 40.47 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+	if math.Abs(rec.RecommendedPrice-1155) > 1e-9 {
+		t.Errorf("RecommendedPrice = %v, want 1155", rec.RecommendedPrice)
+	}
+
+	history, err := client.GetHistoricalPPP(ctx, "tr", 2020, 2022)
+	if err != nil {
+		t.Fatalf("GetHistoricalPPP failed: %v", err)
+	}
+	if len(history) != 3 || history[0].Year != 2022 {
+		t.Errorf("Unexpected history %+v", history)
+	}
+
+	// Countries come from the embedded snapshot unless live lookups are on
+	before := upstream.Requests()
+	countries, err := upstream.Client(ppp.WithLiveCountries()).GetCountries(ctx)
+	if err != nil {
+		t.Fatalf("GetCountries failed: %v", err)
+	}
+	if upstream.Requests() == before {
+		t.Error("Expected live countries to be fetched from the fake server")
+	}
+	// The fixture has 21 countries and the WLD aggregate
+	if len(countries) != 21 {
+		t.Errorf("Expected 21 countries, got %d", len(countries))
+	}
+	for _, c := range countries {
+		if c.ISO2Code == "1W" {
+			t.Error("Expected aggregates to be filtered out")
+		}
+	}
+
+	indicators, err := client.SearchIndicators(ctx, "gdp per capita")
+	if err != nil {
+		t.Fatalf("SearchIndicators failed: %v", err)
+	}
+	if len(indicators) != 3 {
+		t.Errorf("Expected 3 indicators, got %d", len(indicators))
+	}
+
+	if _, err := client.GetPPP(ctx, "ZZ"); err == nil {
+		t.Error("Expected error for unknown country")
+	}
+	if _, err := client.GetExchangeRate(ctx, "XYZ", "USD"); err == nil {
+		t.Error("Expected error for unknown currency")
+	}
+
+	// Cached lookups don't reach the servers
+	before = upstream.Requests()
+	if _, err := client.Recommend(ctx, 100, "USD", "TR"); err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if upstream.Requests() != before {
+		t.Error("Expected second recommendation to be served from cache")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	upstream := NewUpstream(t, nil)
+	ctx := context.Background()
+
+	recording := upstream.Client(ppp.WithoutCache(), ppp.WithHTTPTransport(NewRecorder(dir, nil)))
+	if _, err := recording.GetHistoricalPPP(ctx, "BR", 2014, 2016); err != nil {
+		t.Fatalf("GetHistoricalPPP failed: %v", err)
+	}
+	if _, err := recording.GetHistoricalPPP(ctx, "BR", 2020, 2021); err != nil {
+		t.Fatalf("GetHistoricalPPP failed: %v", err)
+	}
+	if _, err := recording.GetExchangeRate(ctx, "EUR", "TRY"); err != nil {
+		t.Fatalf("GetExchangeRate failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "currency", "currencies", "eur.json")); err != nil {
+		t.Errorf("Expected rate table fixture: %v", err)
+	}
+
+	// Replay the recorded fixtures; both recorded windows were merged
+	replay := NewUpstream(t, os.DirFS(dir)).Client(ppp.WithoutCache())
+	history, err := replay.GetHistoricalPPP(ctx, "BR", 2010, 2030)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(history) != 5 {
+		t.Errorf("Expected 5 merged data points, got %d", len(history))
+	}
+}
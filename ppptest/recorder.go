@@ -0,0 +1,125 @@
+package ppptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that saves successful upstream
+// responses as fixture files, using the layout the fake servers read
+//
+//	client := ppp.NewClient(ppp.WithHTTPTransport(ppptest.NewRecorder("fixtures", nil)))
+type Recorder struct {
+	dir  string
+	base http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecorder creates a recorder writing fixtures under dir
+// base performs the real requests; http.DefaultTransport is used if nil
+func NewRecorder(dir string, base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	
+	fixture, ok := FixturePath(req.URL)
+	if !ok {
+		return resp, nil
+	}
+	
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	
+	if err := r.save(fixture, body); err != nil {
+		return nil, fmt.Errorf("failed to record fixture %s: %w", fixture, err)
+	}
+	
+	return resp, nil
+}
+
+// save writes a fixture, merging indicator series with what was recorded before
+func (r *Recorder) save(fixture string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	filename := filepath.Join(r.dir, filepath.FromSlash(fixture))
+	if existing, err := os.ReadFile(filename); err == nil {
+		if merged, ok := mergeSeries(existing, body); ok {
+			body = merged
+		}
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	
+	return os.WriteFile(filename, body, 0644)
+}
+
+// mergeSeries merges two World Bank indicator responses by date, newest first
+// Returns false if either body is not an indicator series
+func mergeSeries(existing, recorded []byte) ([]byte, bool) {
+	var oldResp, newResp []json.RawMessage
+	if json.Unmarshal(existing, &oldResp) != nil || json.Unmarshal(recorded, &newResp) != nil {
+		return nil, false
+	}
+	if len(oldResp) < 2 || len(newResp) < 2 {
+		return nil, false
+	}
+	
+	var meta map[string]interface{}
+	var oldRows, newRows []map[string]interface{}
+	if json.Unmarshal(newResp[0], &meta) != nil ||
+		json.Unmarshal(oldResp[1], &oldRows) != nil ||
+		json.Unmarshal(newResp[1], &newRows) != nil {
+		return nil, false
+	}
+	
+	byDate := make(map[string]map[string]interface{})
+	for _, rows := range [][]map[string]interface{}{oldRows, newRows} {
+		for _, row := range rows {
+			date, ok := row["date"].(string)
+			if !ok {
+				return nil, false
+			}
+			byDate[date] = row
+		}
+	}
+	
+	merged := make([]map[string]interface{}, 0, len(byDate))
+	for _, row := range byDate {
+		merged = append(merged, row)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i]["date"].(string) > merged[j]["date"].(string)
+	})
+	meta["total"] = len(merged)
+	meta["per_page"] = len(merged)
+	
+	data, err := json.MarshalIndent([]interface{}{meta, merged}, "", " ")
+	if err != nil {
+		return nil, false
+	}
+	
+	return data, true
+}
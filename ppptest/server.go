@@ -0,0 +1,133 @@
+package ppptest
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// invalidValue is the body the World Bank API returns for unknown countries and indicators
+const invalidValue = `[{"message":[{"id":"120","key":"Invalid value","value":"The provided parameter value is not valid"}]}]`
+
+// NewWorldBankHandler returns a handler that mimics the World Bank API
+// Indicator series are filtered by the date query parameter and indicator
+// searches by the search parameter, so one fixture serves any query
+func NewWorldBankHandler(fixtures fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := FixturePath(r.URL)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		
+		// Country codes are case-insensitive upstream
+		parts := strings.Split(fixture, "/")
+		if len(parts) > 2 && parts[1] == "country" {
+			parts[2] = strings.ToUpper(parts[2])
+			fixture = strings.Join(parts, "/")
+		}
+		
+		data, err := fs.ReadFile(fixtures, fixture)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(invalidValue))
+			return
+		}
+		
+		query := r.URL.Query()
+		switch {
+		case query.Get("date") != "":
+			data, err = filterPages(data, func(row map[string]interface{}) bool {
+				return inDateRange(row["date"], query.Get("date"))
+			})
+		case query.Get("search") != "":
+			terms := strings.Fields(strings.ToLower(strings.ReplaceAll(query.Get("search"), "+", " ")))
+			data, err = filterPages(data, func(row map[string]interface{}) bool {
+				name, _ := row["name"].(string)
+				for _, term := range terms {
+					if !strings.Contains(strings.ToLower(name), term) {
+						return false
+					}
+				}
+				return true
+			})
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// NewCurrencyHandler returns a handler that mimics the currency API
+func NewCurrencyHandler(fixtures fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := FixturePath(r.URL)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		
+		data, err := fs.ReadFile(fixtures, strings.ToLower(fixture))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// filterPages keeps the rows of a World Bank [meta, rows] response that match
+func filterPages(data []byte, keep func(map[string]interface{}) bool) ([]byte, error) {
+	var response []json.RawMessage
+	if err := json.Unmarshal(data, &response); err != nil || len(response) < 2 {
+		return data, err
+	}
+	
+	var meta map[string]interface{}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(response[0], &meta); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(response[1], &rows); err != nil {
+		return nil, err
+	}
+	
+	filtered := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if keep(row) {
+			filtered = append(filtered, row)
+		}
+	}
+	meta["total"] = len(filtered)
+	
+	return json.Marshal([]interface{}{meta, filtered})
+}
+
+// inDateRange reports whether a row date falls in a "2014:2024" or "2023" range
+func inDateRange(date interface{}, dateRange string) bool {
+	s, _ := date.(string)
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return false
+	}
+	
+	start, end, found := strings.Cut(dateRange, ":")
+	if !found {
+		end = start
+	}
+	startYear, err1 := strconv.Atoi(start)
+	endYear, err2 := strconv.Atoi(end)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	
+	return year >= startYear && year <= endYear
+}
@@ -0,0 +1,463 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Catalog is a list of products and plans priced in one currency
+type Catalog struct {
+	Currency string        `json:"currency"`
+	Items    []CatalogItem `json:"items"`
+}
+
+// CatalogItem is a product or one of its plans with a base price
+// Example: CatalogItem{Product: "pro", Plan: "monthly", Price: 29}
+type CatalogItem struct {
+	Product string  `json:"product"`
+	Plan    string  `json:"plan,omitempty"`
+	Price   float64 `json:"price"`
+}
+
+// PriceRounding controls how localized prices are rounded
+type PriceRounding int
+
+const (
+	// RoundCash rounds to the smallest cash denomination, like RoundPrice
+	RoundCash PriceRounding = iota
+	// RoundWhole rounds to whole units of the currency
+	RoundWhole
+	// RoundCharm rounds to whole units and ends prices in 9s: 9.99 or,
+	// for currencies without minor units, 1199
+	RoundCharm
+)
+
+// Policy limits recorded in PriceProvenance.Limit
+const (
+	LimitMaxDiscount = "max_discount"
+	LimitNoMarkup    = "no_markup"
+)
+
+// PricingPolicy adjusts PPP prices before they enter a price book
+type PricingPolicy struct {
+	// MaxDiscount caps the discount off the market price, in percent
+	// Zero allows any discount
+	MaxDiscount float64
+	
+	// NoMarkup keeps prices at or below the market price in countries
+	// where the PPP price would be higher
+	NoMarkup bool
+	
+	// Rounding controls how prices are rounded, RoundCash by default
+	Rounding PriceRounding
+}
+
+// PriceProvenance records the data and policy behind a localized price
+type PriceProvenance struct {
+	PPPFactor        float64   `json:"ppp_factor"`
+	PPPYear          int       `json:"ppp_year"`
+	PPPSource        string    `json:"ppp_source,omitempty"`
+	ExchangeRate     float64   `json:"exchange_rate"` // catalog currency to local currency
+	ExchangeRateDate time.Time `json:"exchange_rate_date"`
+	USDRate          float64   `json:"usd_rate,omitempty"` // catalog currency to USD, for non-USD catalogs
+	Limit            string    `json:"limit,omitempty"`    // policy limit applied, if any
+}
+
+// PriceBookEntry is the localized price of a catalog item in one country
+type PriceBookEntry struct {
+	Product            string          `json:"product"`
+	Plan               string          `json:"plan,omitempty"`
+	Country            string          `json:"country"`
+	Base               Money           `json:"base"`
+	Price              Money           `json:"price"`
+	MarketPrice        Money           `json:"market_price"` // base price at the market exchange rate
+	DiscountPercentage float64         `json:"discount_percentage"`
+	Provenance         PriceProvenance `json:"provenance"`
+}
+
+// PriceBook is a table of localized prices for a catalog
+type PriceBook struct {
+	Currency    string           `json:"currency"`
+	Countries   []string         `json:"countries"`
+	Entries     []PriceBookEntry `json:"entries"` // by catalog item, then country
+	GeneratedAt time.Time        `json:"generated_at"`
+	AsOf        *time.Time       `json:"as_of,omitempty"`
+	
+	// Failed holds the countries that could not be priced
+	Failed map[string]error `json:"-"`
+}
+
+// Lookup returns the entry for a product, plan and country
+func (b *PriceBook) Lookup(product, plan, country string) (PriceBookEntry, bool) {
+	country = canonicalCountry(country)
+	for _, e := range b.Entries {
+		if e.Product == product && e.Plan == plan && e.Country == country {
+			return e, true
+		}
+	}
+	return PriceBookEntry{}, false
+}
+
+// PriceBookBuilder computes price books, fetching PPP data once per country
+// and one rate table per catalog currency
+type PriceBookBuilder struct {
+	client    *Client
+	countries []string
+	policy    PricingPolicy
+}
+
+// PriceBookOption is a functional option for configuring a PriceBookBuilder
+type PriceBookOption func(*PriceBookBuilder)
+
+// WithPriceBookCountries sets the countries to price (or All, the default)
+func WithPriceBookCountries(codes ...string) PriceBookOption {
+	return func(b *PriceBookBuilder) {
+		b.countries = codes
+	}
+}
+
+// WithPricingPolicy sets the discount limits and rounding of localized prices
+func WithPricingPolicy(policy PricingPolicy) PriceBookOption {
+	return func(b *PriceBookBuilder) {
+		b.policy = policy
+	}
+}
+
+// NewPriceBookBuilder creates a price book builder for a client
+func NewPriceBookBuilder(client *Client, opts ...PriceBookOption) *PriceBookBuilder {
+	b := &PriceBookBuilder{
+		client:    client,
+		countries: []string{All},
+	}
+	
+	for _, opt := range opts {
+		opt(b)
+	}
+	
+	return b
+}
+
+// Build prices every catalog item in every country
+// Non-USD catalogs are converted to US dollars at the market rate before the
+// PPP factor is applied. Countries without data are left out of the book,
+// listed in Failed and returned joined together as the error
+// Honours an as-of date set with ContextWithAsOf
+func (b *PriceBookBuilder) Build(ctx context.Context, catalog Catalog) (*PriceBook, error) {
+	base := strings.ToUpper(catalog.Currency)
+	if err := b.validate(base, catalog.Items); err != nil {
+		return nil, err
+	}
+	
+	countries, err := b.countryCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	book := &PriceBook{
+		Currency:    base,
+		GeneratedAt: b.client.now(),
+		Failed:      make(map[string]error),
+	}
+	if date, ok := AsOfFromContext(ctx); ok {
+		book.AsOf = &date
+	}
+	
+	rates := newRateSource(b.client, base)
+	usdRate := 1.0
+	if base != "USD" {
+		rate, err := rates.rate(ctx, "USD")
+		if err != nil {
+			return nil, err
+		}
+		usdRate = rate.Rate
+	}
+	
+	prices := make(map[string][]PriceBookEntry, len(countries))
+	for _, country := range countries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		
+		entries, err := b.priceCountry(ctx, rates, catalog.Items, base, country, usdRate)
+		if err != nil {
+			book.Failed[country] = err
+			continue
+		}
+		prices[country] = entries
+		book.Countries = append(book.Countries, country)
+	}
+	
+	for i := range catalog.Items {
+		for _, country := range book.Countries {
+			book.Entries = append(book.Entries, prices[country][i])
+		}
+	}
+	
+	var errs []error
+	for _, country := range countries {
+		if err, ok := book.Failed[country]; ok {
+			errs = append(errs, fmt.Errorf("%s: %w", country, err))
+		}
+	}
+	
+	return book, errors.Join(errs...)
+}
+
+// validate checks the catalog currency and items
+func (b *PriceBookBuilder) validate(base string, items []CatalogItem) error {
+	if err := ValidateCurrencyCode(base); err != nil {
+		return err
+	}
+	
+	if len(items) == 0 {
+		return NewPPPError(ErrCodeInvalidInput, "no items provided", nil)
+	}
+	
+	seen := make(map[CatalogItem]bool, len(items))
+	for _, item := range items {
+		key := CatalogItem{Product: item.Product, Plan: item.Plan}
+		if item.Product == "" {
+			return NewPPPError(ErrCodeInvalidInput, "catalog item has no product", nil)
+		}
+		if seen[key] {
+			return NewPPPError(ErrCodeInvalidInput, "duplicate catalog item", nil).
+				WithContext("product", item.Product).
+				WithContext("plan", item.Plan)
+		}
+		seen[key] = true
+		
+		if err := ValidateAmount(item.Price); err != nil {
+			return fmt.Errorf("invalid price for item %s: %w", strings.TrimSuffix(item.Product+"/"+item.Plan, "/"), err)
+		}
+	}
+	
+	return nil
+}
+
+// countryCodes resolves the configured countries, dropping duplicates and
+// World Bank aggregates
+func (b *PriceBookBuilder) countryCodes(ctx context.Context) ([]string, error) {
+	codes := b.countries
+	if containsAll(codes) {
+		countries, err := b.client.GetCountries(ctx)
+		if err != nil {
+			return nil, err
+		}
+		codes = make([]string, 0, len(countries))
+		for _, country := range countries {
+			if _, err := NormalizeCountryCode(country.ISO2Code); err == nil {
+				codes = append(codes, country.ISO2Code)
+			}
+		}
+		sort.Strings(codes)
+	}
+	
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalized, err := NormalizeCountryCode(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	
+	return result, nil
+}
+
+// priceCountry prices every catalog item in one country
+func (b *PriceBookBuilder) priceCountry(ctx context.Context, rates *rateSource, items []CatalogItem, base, country string, usdRate float64) ([]PriceBookEntry, error) {
+	ppp, err := b.client.GetPPP(ctx, country)
+	if err != nil {
+		return nil, err
+	}
+	
+	currency := b.client.getCurrencyForCountry(country)
+	rate, err := rates.rate(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	
+	provenance := PriceProvenance{
+		PPPFactor:        ppp.Factor,
+		PPPYear:          ppp.Year,
+		PPPSource:        ppp.Source,
+		ExchangeRate:     rate.Rate,
+		ExchangeRateDate: rate.LastUpdated,
+	}
+	if base != "USD" {
+		provenance.USDRate = usdRate
+	}
+	
+	entries := make([]PriceBookEntry, len(items))
+	for i, item := range items {
+		entries[i], err = b.priceItem(item, base, currency, country, provenance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	return entries, nil
+}
+
+// priceItem applies the PPP factor and the pricing policy to one item
+func (b *PriceBookBuilder) priceItem(item CatalogItem, base, currency, country string, provenance PriceProvenance) (PriceBookEntry, error) {
+	market := item.Price * provenance.ExchangeRate
+	price := item.Price * provenance.PPPFactor
+	if provenance.USDRate > 0 {
+		price = item.Price * provenance.USDRate * provenance.PPPFactor
+	}
+	
+	// Round first and clamp afterwards, so rounding can't break the policy:
+	// discount floors round up and the no-markup ceiling rounds down
+	price = roundLocalPrice(price, currency, b.policy.Rounding)
+	if limit := b.policy.MaxDiscount; limit > 0 && price < market*(1-limit/100) {
+		price = roundLocalPriceUp(market*(1-limit/100), currency, b.policy.Rounding)
+		provenance.Limit = LimitMaxDiscount
+	}
+	if b.policy.NoMarkup && price > market {
+		price = roundLocalPriceDown(market, currency, b.policy.Rounding)
+		provenance.Limit = LimitNoMarkup
+	}
+	
+	baseMoney, err := MoneyFromFloat(item.Price, base)
+	if err != nil {
+		return PriceBookEntry{}, err
+	}
+	marketMoney, err := MoneyFromFloat(market, currency)
+	if err != nil {
+		return PriceBookEntry{}, err
+	}
+	priceMoney, err := MoneyFromFloat(price, currency)
+	if err != nil {
+		return PriceBookEntry{}, err
+	}
+	
+	discount := 0.0
+	if market > 0 {
+		discount = (market - priceMoney.Float64()) / market * 100
+	}
+	
+	return PriceBookEntry{
+		Product:            item.Product,
+		Plan:               item.Plan,
+		Country:            country,
+		Base:               baseMoney,
+		Price:              priceMoney,
+		MarketPrice:        marketMoney,
+		DiscountPercentage: discount,
+		Provenance:         provenance,
+	}, nil
+}
+
+// priceGrid describes the prices a rounding mode produces: multiples of
+// step minus offset, no lower than lowest
+// Example: RoundCharm in USD gives step 1, offset 0.01 and lowest 0.99
+func priceGrid(currency string, rounding PriceRounding) (step, offset, lowest float64) {
+	switch rounding {
+	case RoundWhole:
+		return 1, 0, 1
+	case RoundCharm:
+		if currencyDigits(currency) == 0 {
+			return 10, 1, 9
+		}
+		offset = math.Pow10(-currencyDigits(currency))
+		return 1, offset, 1 - offset
+	default:
+		return math.Pow10(-currencyCashDigits(currency)), 0, 0
+	}
+}
+
+// roundLocalPrice rounds a localized price according to the rounding mode
+func roundLocalPrice(price float64, currency string, rounding PriceRounding) float64 {
+	if rounding == RoundCash {
+		return RoundPrice(price, currency)
+	}
+	step, offset, lowest := priceGrid(currency, rounding)
+	return roundMinor(math.Max(lowest, math.Round(price/step)*step-offset), currency)
+}
+
+// gridEpsilon absorbs float error when a price already lies on the grid
+const gridEpsilon = 1e-9
+
+// roundLocalPriceUp returns the lowest price of the rounding mode at or
+// above price
+func roundLocalPriceUp(price float64, currency string, rounding PriceRounding) float64 {
+	step, offset, lowest := priceGrid(currency, rounding)
+	return roundMinor(math.Max(lowest, math.Ceil((price+offset)/step-gridEpsilon)*step-offset), currency)
+}
+
+// roundLocalPriceDown returns the highest price of the rounding mode at or
+// below price. Prices under the lowest rounded price are rounded down to
+// the currency's cash unit instead, so the result never exceeds price
+func roundLocalPriceDown(price float64, currency string, rounding PriceRounding) float64 {
+	step, offset, lowest := priceGrid(currency, rounding)
+	rounded := math.Floor((price+offset)/step+gridEpsilon)*step - offset
+	if rounded < lowest {
+		step = math.Pow10(-currencyCashDigits(currency))
+		rounded = math.Floor(price/step+gridEpsilon) * step
+	}
+	return roundMinor(rounded, currency)
+}
+
+// roundMinor removes float noise by rounding to the currency's minor unit
+func roundMinor(price float64, currency string) float64 {
+	scale := math.Pow10(currencyDigits(currency))
+	return math.Round(price*scale) / scale
+}
+
+// rateSource serves exchange rates from one currency, fetching its rate
+// table at most once
+type rateSource struct {
+	client  *Client
+	base    string
+	table   *RateTable
+	fetched bool
+}
+
+// newRateSource creates a rate source for a base currency
+func newRateSource(client *Client, base string) *rateSource {
+	return &rateSource{client: client, base: base}
+}
+
+// rate returns the exchange rate from the base currency to currency
+// Falls back to single rate lookups, which may be served from the cache,
+// when the table is unavailable or lacks the currency
+func (s *rateSource) rate(ctx context.Context, currency string) (*ExchangeRate, error) {
+	if currency == s.base {
+		return &ExchangeRate{From: s.base, To: currency, Rate: 1, LastUpdated: s.client.now()}, nil
+	}
+	
+	if !s.fetched {
+		s.fetched = true
+		s.table, _ = s.fetchTable(ctx)
+	}
+	if s.table != nil {
+		if rate, ok := s.table.Rates[currency]; ok && rate > 0 {
+			return &ExchangeRate{From: s.base, To: currency, Rate: rate, LastUpdated: s.table.LastUpdated}, nil
+		}
+	}
+	
+	return s.client.GetExchangeRate(ctx, s.base, currency)
+}
+
+// fetchTable fetches the current or as-of rate table for the base currency
+func (s *rateSource) fetchTable(ctx context.Context) (*RateTable, error) {
+	date, ok := AsOfFromContext(ctx)
+	if !ok {
+		return s.client.GetRateTable(ctx, s.base)
+	}
+	
+	if err := s.client.validateAsOf(date); err != nil {
+		return nil, err
+	}
+	return s.client.currency.GetRateTableAt(ctx, s.base, date)
+}
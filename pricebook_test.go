@@ -0,0 +1,266 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newPriceBookClient starts fake upstreams and returns a client without a
+// cache, so every lookup the builder makes reaches them
+func newPriceBookClient(t *testing.T, factors map[string]float64) (*Client, *int32, *int32) {
+	t.Helper()
+	var pppRequests, rateRequests int32
+
+	worldBank := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pppRequests, 1)
+		country := strings.Split(strings.TrimPrefix(r.URL.Path, "/country/"), "/")[0]
+		factor, ok := factors[country]
+		if !ok {
+			fmt.Fprint(w, `[{"page":1},[]]`)
+			return
+		}
+		fmt.Fprintf(w, `[{"page":1},[{"country":{"id":%q,"value":%q},"date":"2023","value":%v}]]`, country, country, factor)
+	}))
+	t.Cleanup(worldBank.Close)
+
+	currency := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&rateRequests, 1)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/usd.json"):
+			fmt.Fprint(w, `{"date":"2024-05-01","usd":{"try":40.47,"inr":83,"eur":0.92,"jpy":150,"chf":0.8014}}`)
+		case strings.HasSuffix(r.URL.Path, "/eur.json"):
+			fmt.Fprint(w, `{"date":"2024-05-01","eur":{"usd":1.1,"try":44}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(currency.Close)
+
+	client := NewClient(WithoutCache(), WithWorldBankURL(worldBank.URL), WithCurrencyURL(currency.URL))
+	return client, &pppRequests, &rateRequests
+}
+
+var testCatalog = Catalog{
+	Currency: "usd",
+	Items: []CatalogItem{
+		{Product: "pro", Plan: "monthly", Price: 10},
+		{Product: "pro", Plan: "annual", Price: 100},
+	},
+}
+
+func TestPriceBook(t *testing.T) {
+	client, pppRequests, rateRequests := newPriceBookClient(t, map[string]float64{"TR": 11.55, "IN": 22, "DE": 0.75, "US": 1})
+
+	builder := NewPriceBookBuilder(client, WithPriceBookCountries("TR", "IN", "DE", "US", "AF", "tur"))
+	book, err := builder.Build(context.Background(), testCatalog)
+	if err == nil || !IsNoDataError(book.Failed["AF"]) {
+		t.Errorf("Expected AF to fail with NO_DATA, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(pppRequests); got != 5 {
+		t.Errorf("Expected one PPP request per country, got %d", got)
+	}
+	if got := atomic.LoadInt32(rateRequests); got != 1 {
+		t.Errorf("Expected one rate table request, got %d", got)
+	}
+
+	if len(book.Countries) != 4 || len(book.Entries) != 8 {
+		t.Fatalf("Expected 4 countries and 8 entries, got %v and %d", book.Countries, len(book.Entries))
+	}
+	if e := book.Entries[0]; e.Product != "pro" || e.Plan != "monthly" || e.Country != "TR" {
+		t.Errorf("Expected entries ordered by item then country, got %+v", e)
+	}
+
+	tr, ok := book.Lookup("pro", "monthly", "TUR")
+	if !ok {
+		t.Fatal("Expected an entry for TR")
+	}
+	if tr.Price != NewMoney(11550, "TRY") || tr.MarketPrice != NewMoney(40470, "TRY") || tr.Base != NewMoney(1000, "USD") {
+		t.Errorf("Unexpected TR prices %+v", tr)
+	}
+	if math.Abs(tr.DiscountPercentage-71.46) > 0.01 {
+		t.Errorf("TR discount = %.2f, want 71.46", tr.DiscountPercentage)
+	}
+	if p := tr.Provenance; p.PPPFactor != 11.55 || p.PPPYear != 2023 || p.ExchangeRate != 40.47 || p.USDRate != 0 || p.Limit != "" {
+		t.Errorf("Unexpected provenance %+v", p)
+	}
+
+	if us, _ := book.Lookup("pro", "annual", "US"); us.Price != NewMoney(10000, "USD") || us.DiscountPercentage != 0 {
+		t.Errorf("Expected the base price in the base country, got %+v", us)
+	}
+}
+
+func TestPriceBookPolicy(t *testing.T) {
+	client, _, _ := newPriceBookClient(t, map[string]float64{"TR": 11.55, "DE": 1})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		policy  PricingPolicy
+		country string
+		price   Money
+		limit   string
+	}{
+		{"max discount", PricingPolicy{MaxDiscount: 50}, "TR", NewMoney(20235, "TRY"), LimitMaxDiscount},
+		{"within max discount", PricingPolicy{MaxDiscount: 80}, "TR", NewMoney(11550, "TRY"), ""},
+		{"markup", PricingPolicy{}, "DE", NewMoney(1000, "EUR"), ""},
+		{"no markup", PricingPolicy{NoMarkup: true}, "DE", NewMoney(920, "EUR"), LimitNoMarkup},
+		{"whole", PricingPolicy{Rounding: RoundWhole}, "TR", NewMoney(11600, "TRY"), ""},
+		{"charm", PricingPolicy{Rounding: RoundCharm}, "TR", NewMoney(11599, "TRY"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book, err := NewPriceBookBuilder(client, WithPriceBookCountries(tt.country), WithPricingPolicy(tt.policy)).Build(ctx, testCatalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, _ := book.Lookup("pro", "monthly", tt.country)
+			if e.Price != tt.price || e.Provenance.Limit != tt.limit {
+				t.Errorf("Got %s (%q), want %s (%q)", e.Price, e.Provenance.Limit, tt.price, tt.limit)
+			}
+		})
+	}
+}
+
+// TestPriceBookPolicyRounding checks that rounding never breaks the policy:
+// in Switzerland the PPP price is above the market price
+func TestPriceBookPolicyRounding(t *testing.T) {
+	client, _, _ := newPriceBookClient(t, map[string]float64{"CH": 1.2, "TR": 11.55})
+	ctx := context.Background()
+	catalog := Catalog{Currency: "USD", Items: []CatalogItem{{Product: "pro", Price: 10.7}}}
+
+	tests := []struct {
+		name    string
+		policy  PricingPolicy
+		country string
+		price   Money
+		limit   string
+	}{
+		{"no markup cash", PricingPolicy{NoMarkup: true}, "CH", NewMoney(857, "CHF"), LimitNoMarkup},
+		{"no markup whole", PricingPolicy{NoMarkup: true, Rounding: RoundWhole}, "CH", NewMoney(800, "CHF"), LimitNoMarkup},
+		{"no markup charm", PricingPolicy{NoMarkup: true, Rounding: RoundCharm}, "CH", NewMoney(799, "CHF"), LimitNoMarkup},
+		{"markup charm", PricingPolicy{Rounding: RoundCharm}, "CH", NewMoney(1299, "CHF"), ""},
+		{"max discount whole", PricingPolicy{MaxDiscount: 50, Rounding: RoundWhole}, "TR", NewMoney(21700, "TRY"), LimitMaxDiscount},
+		{"max discount charm", PricingPolicy{MaxDiscount: 50, Rounding: RoundCharm}, "TR", NewMoney(21699, "TRY"), LimitMaxDiscount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book, err := NewPriceBookBuilder(client, WithPriceBookCountries(tt.country), WithPricingPolicy(tt.policy)).Build(ctx, catalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, _ := book.Lookup("pro", "", tt.country)
+			if e.Price != tt.price || e.Provenance.Limit != tt.limit {
+				t.Errorf("Got %s (%q), want %s (%q)", e.Price, e.Provenance.Limit, tt.price, tt.limit)
+			}
+			if tt.policy.NoMarkup && e.DiscountPercentage < 0 {
+				t.Errorf("Expected no markup, got a %.2f%% discount", e.DiscountPercentage)
+			}
+			if limit := tt.policy.MaxDiscount; limit > 0 && e.DiscountPercentage > limit {
+				t.Errorf("Expected at most %v%% off, got %.2f%%", limit, e.DiscountPercentage)
+			}
+		})
+	}
+}
+
+func TestPriceBookNonUSDCatalog(t *testing.T) {
+	client, _, rateRequests := newPriceBookClient(t, map[string]float64{"TR": 11.55})
+
+	catalog := Catalog{Currency: "EUR", Items: []CatalogItem{{Product: "pro", Price: 10}}}
+	book, err := NewPriceBookBuilder(client, WithPriceBookCountries("TR")).Build(context.Background(), catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 EUR is 11 USD, priced at 11.55 TRY per international dollar
+	e, _ := book.Lookup("pro", "", "TR")
+	if e.Price != NewMoney(12705, "TRY") || e.MarketPrice != NewMoney(44000, "TRY") || e.Provenance.USDRate != 1.1 {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if got := atomic.LoadInt32(rateRequests); got != 1 {
+		t.Errorf("Expected one rate table request, got %d", got)
+	}
+}
+
+// isInvalidInput reports whether err is an INVALID_INPUT PPPError
+func isInvalidInput(err error) bool {
+	var pppErr *PPPError
+	return errors.As(err, &pppErr) && pppErr.Code == ErrCodeInvalidInput
+}
+
+func TestPriceBookValidation(t *testing.T) {
+	client, _, _ := newPriceBookClient(t, nil)
+	builder := NewPriceBookBuilder(client, WithPriceBookCountries("TR"))
+	ctx := context.Background()
+
+	catalogs := []Catalog{
+		{Currency: "XXX1", Items: testCatalog.Items},
+		{Currency: "USD"},
+		{Currency: "USD", Items: []CatalogItem{{Plan: "monthly", Price: 10}}},
+		{Currency: "USD", Items: []CatalogItem{{Product: "pro", Price: 10}, {Product: "pro", Price: 20}}},
+		{Currency: "USD", Items: []CatalogItem{{Product: "pro", Price: -1}}},
+	}
+	for _, catalog := range catalogs {
+		if _, err := builder.Build(ctx, catalog); !isInvalidInput(err) {
+			t.Errorf("Build(%+v) = %v, want INVALID_INPUT", catalog, err)
+		}
+	}
+
+	if _, err := NewPriceBookBuilder(client, WithPriceBookCountries("ZZ")).Build(ctx, testCatalog); !isInvalidInput(err) {
+		t.Errorf("Expected INVALID_INPUT for an unknown country, got %v", err)
+	}
+}
+
+func TestRoundLocalPrice(t *testing.T) {
+	tests := []struct {
+		price    float64
+		currency string
+		rounding PriceRounding
+		want     float64
+	}{
+		{9.404, "USD", RoundCash, 9.4},
+		{9.404, "USD", RoundCharm, 8.99},
+		{9.6, "USD", RoundCharm, 9.99},
+		{0.2, "USD", RoundCharm, 0.99},
+		{1203, "JPY", RoundCharm, 1199},
+		{3, "JPY", RoundCharm, 9},
+		{1203.4, "JPY", RoundWhole, 1203},
+	}
+	for _, tt := range tests {
+		if got := roundLocalPrice(tt.price, tt.currency, tt.rounding); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("roundLocalPrice(%v, %s, %d) = %v, want %v", tt.price, tt.currency, tt.rounding, got, tt.want)
+		}
+	}
+
+	directed := []struct {
+		price    float64
+		currency string
+		rounding PriceRounding
+		down, up float64
+	}{
+		{8.57, "CHF", RoundCash, 8.57, 8.57},
+		{8.575, "CHF", RoundWhole, 8, 9},
+		{8.575, "CHF", RoundCharm, 7.99, 8.99},
+		{8.99, "USD", RoundCharm, 8.99, 8.99},
+		{1203, "JPY", RoundCharm, 1199, 1209},
+		{0.5, "USD", RoundCharm, 0.5, 0.99},
+		{5, "JPY", RoundCharm, 5, 9},
+	}
+	for _, tt := range directed {
+		if got := roundLocalPriceDown(tt.price, tt.currency, tt.rounding); math.Abs(got-tt.down) > 1e-9 {
+			t.Errorf("roundLocalPriceDown(%v, %s, %d) = %v, want %v", tt.price, tt.currency, tt.rounding, got, tt.down)
+		}
+		if got := roundLocalPriceUp(tt.price, tt.currency, tt.rounding); math.Abs(got-tt.up) > 1e-9 {
+			t.Errorf("roundLocalPriceUp(%v, %s, %d) = %v, want %v", tt.price, tt.currency, tt.rounding, got, tt.up)
+		}
+	}
+}
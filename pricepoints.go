@@ -0,0 +1,347 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Stores with price point ladders
+const (
+	StoreAppStore   = "appstore"
+	StoreGooglePlay = "googleplay"
+)
+
+// SnapMode controls which price point a price snaps to
+type SnapMode int
+
+const (
+	// SnapNearest snaps to the closest price point, the higher one on ties
+	SnapNearest SnapMode = iota
+	// SnapUp snaps to the lowest price point at or above the price
+	SnapUp
+	// SnapDown snaps to the highest price point at or below the price
+	SnapDown
+)
+
+// String returns the mode name
+func (m SnapMode) String() string {
+	switch m {
+	case SnapUp:
+		return "up"
+	case SnapDown:
+		return "down"
+	default:
+		return "nearest"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m SnapMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+//go:generate go run ./internal/pricepointgen -o data/pricepoints.json -appstore $APPSTORE_PRICE_POINTS -appstore-version $APPSTORE_PRICE_POINTS_VERSION -googleplay $GOOGLEPLAY_PRICE_RANGES -googleplay-version $GOOGLEPLAY_PRICE_RANGES_VERSION -retrieved $PRICE_POINTS_RETRIEVED
+
+// pricePointData holds the App Store and Google Play price point ladders
+// as (first, last, step) segments per currency, with the version, source
+// and retrieval date of the store exports they were generated from
+//go:embed data/pricepoints.json
+var pricePointData []byte
+
+// pricePointStore is the parsed ladder data of one store
+type pricePointStore struct {
+	version   string
+	source    string
+	retrieved string
+	ladders   map[string][]PricePointRange
+}
+
+var (
+	pricePointsOnce sync.Once
+	pricePointsMu   sync.RWMutex
+	pricePointIndex map[string]*pricePointStore
+)
+
+// loadPricePoints parses the embedded price point data once
+func loadPricePoints() map[string]*pricePointStore {
+	pricePointsOnce.Do(func() {
+		stores, err := parsePricePoints(pricePointData)
+		if err != nil {
+			panic("ppp: invalid embedded price point data: " + err.Error())
+		}
+		pricePointIndex = stores
+	})
+	return pricePointIndex
+}
+
+// parsePricePoints parses and validates price point data
+func parsePricePoints(data []byte) (map[string]*pricePointStore, error) {
+	var raw map[string]struct {
+		Version    string                 `json:"version"`
+		Source     string                 `json:"source"`
+		Retrieved  string                 `json:"retrieved"`
+		Currencies map[string][][3]string `json:"currencies"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	
+	stores := make(map[string]*pricePointStore, len(raw))
+	for store, d := range raw {
+		s := &pricePointStore{
+			version:   d.Version,
+			source:    d.Source,
+			retrieved: d.Retrieved,
+			ladders:   make(map[string][]PricePointRange, len(d.Currencies)),
+		}
+		for currency, segments := range d.Currencies {
+			currency = strings.ToUpper(currency)
+			ranges, err := parseSegments(currency, segments)
+			if err != nil {
+				return nil, err
+			}
+			s.ladders[currency] = ranges
+		}
+		stores[strings.ToLower(store)] = s
+	}
+	return stores, nil
+}
+
+// LoadPricePoints loads price point ladders in the format of the embedded
+// data, as written by internal/pricepointgen from store exports. Each store
+// in the data replaces the ladders of that store; other stores are kept
+func LoadPricePoints(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	stores, err := parsePricePoints(data)
+	if err != nil {
+		return NewPPPError(ErrCodeInvalidInput, "invalid price point data: "+err.Error(), err)
+	}
+	
+	loadPricePoints()
+	pricePointsMu.Lock()
+	defer pricePointsMu.Unlock()
+	merged := make(map[string]*pricePointStore, len(pricePointIndex)+len(stores))
+	for store, s := range pricePointIndex {
+		merged[store] = s
+	}
+	for store, s := range stores {
+		merged[store] = s
+	}
+	pricePointIndex = merged
+	return nil
+}
+
+// pricePointStoreFor returns the loaded ladders of a store
+func pricePointStoreFor(store string) (*pricePointStore, bool) {
+	loadPricePoints()
+	pricePointsMu.RLock()
+	defer pricePointsMu.RUnlock()
+	s, ok := pricePointIndex[strings.ToLower(store)]
+	return s, ok
+}
+
+// parseSegments parses (first, last, step) segments in ascending order
+func parseSegments(currency string, segments [][3]string) ([]PricePointRange, error) {
+	ranges := make([]PricePointRange, 0, len(segments))
+	for _, segment := range segments {
+		var bounds [3]Money
+		for i, value := range segment {
+			m, err := ParseMoney(value, currency)
+			if err != nil {
+				return nil, fmt.Errorf("%s %v: %w", currency, segment, err)
+			}
+			bounds[i] = m
+		}
+		
+		r := PricePointRange{First: bounds[0], Last: bounds[1], Step: bounds[2]}
+		if r.First.Amount <= 0 || r.Step.Amount <= 0 || r.Last.Amount < r.First.Amount ||
+			(r.Last.Amount-r.First.Amount)%r.Step.Amount != 0 ||
+			(len(ranges) > 0 && r.First.Amount <= ranges[len(ranges)-1].Last.Amount) {
+			return nil, fmt.Errorf("%s: invalid segment %v", currency, segment)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// PricePointRange is a run of evenly spaced price points, First and Last
+// included
+// Example: {0.29, 0.49, 0.10} USD is 0.29, 0.39 and 0.49
+type PricePointRange struct {
+	First Money `json:"first"`
+	Last  Money `json:"last"`
+	Step  Money `json:"step"`
+}
+
+// PricePointLadder is the list of prices a store accepts in a currency
+// Google Play accepts any price within a range, so its ladders are a single
+// range stepping by the currency's minor unit
+type PricePointLadder struct {
+	Store     string            `json:"store"`
+	Currency  string            `json:"currency"`
+	Version   string            `json:"version"`             // store version of the price points
+	Source    string            `json:"source,omitempty"`    // export the ladder was generated from
+	Retrieved string            `json:"retrieved,omitempty"` // date the export was taken
+	Ranges    []PricePointRange `json:"ranges"`              // in ascending order
+}
+
+// Len returns the number of price points
+func (l *PricePointLadder) Len() int {
+	n := 0
+	for _, r := range l.Ranges {
+		n += int((r.Last.Amount-r.First.Amount)/r.Step.Amount) + 1
+	}
+	return n
+}
+
+// Points lists every price point in ascending order
+// Google Play ladders have one point per minor unit, so prefer Snap
+func (l *PricePointLadder) Points() []Money {
+	points := make([]Money, 0, l.Len())
+	for _, r := range l.Ranges {
+		for amount := r.First.Amount; amount <= r.Last.Amount; amount += r.Step.Amount {
+			points = append(points, Money{Amount: amount, Currency: l.Currency})
+		}
+	}
+	return points
+}
+
+// PricePoints returns the price point ladder of a store in a currency
+// The embedded data only has the ladders generated from store exports; use
+// LoadPricePoints to add your own
+func PricePoints(store, currency string) (*PricePointLadder, bool) {
+	s, ok := pricePointStoreFor(store)
+	if !ok {
+		return nil, false
+	}
+	currency = strings.ToUpper(currency)
+	ranges, ok := s.ladders[currency]
+	if !ok {
+		return nil, false
+	}
+	return &PricePointLadder{
+		Store:     strings.ToLower(store),
+		Currency:  currency,
+		Version:   s.version,
+		Source:    s.source,
+		Retrieved: s.retrieved,
+		Ranges:    append([]PricePointRange(nil), ranges...),
+	}, true
+}
+
+// PricePointCurrencies returns the currencies a store has a ladder for, sorted
+func PricePointCurrencies(store string) []string {
+	s, ok := pricePointStoreFor(store)
+	if !ok {
+		return nil
+	}
+	currencies := make([]string, 0, len(s.ladders))
+	for currency := range s.ladders {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// PricePointSnap is a price snapped to a store price point
+type PricePointSnap struct {
+	Store               string   `json:"store"`
+	Version             string   `json:"version"`
+	Mode                SnapMode `json:"mode"`
+	RawPrice            float64  `json:"raw_price"`
+	Price               Money    `json:"price"`
+	Deviation           float64  `json:"deviation"`            // Price - RawPrice
+	DeviationPercentage float64  `json:"deviation_percentage"` // Deviation relative to RawPrice
+}
+
+// Snap snaps price to a point of the ladder
+// Prices outside the ladder snap to its first or last point in every mode
+func (l *PricePointLadder) Snap(price float64, mode SnapMode) (*PricePointSnap, error) {
+	if err := ValidateAmount(price); err != nil {
+		return nil, err
+	}
+	if len(l.Ranges) == 0 {
+		return nil, NewPPPError(ErrCodeNoData, "price point ladder is empty", ErrNoData).
+			WithContext("store", l.Store).
+			WithContext("currency", l.Currency)
+	}
+	
+	// Minor units, rounded to absorb float error such as 4.99 * 100
+	minor := math.Round(price*math.Pow10(currencyDigits(l.Currency))*1e6) / 1e6
+	below, above, hasBelow, hasAbove := l.neighbours(minor)
+	
+	var amount int64
+	switch {
+	case !hasAbove:
+		amount = below
+	case !hasBelow || below == above:
+		amount = above
+	case mode == SnapUp:
+		amount = above
+	case mode == SnapDown:
+		amount = below
+	default:
+		amount = above
+		if minor-float64(below) < float64(above)-minor {
+			amount = below
+		}
+	}
+	
+	point := Money{Amount: amount, Currency: l.Currency}
+	deviation := point.Float64() - price
+	return &PricePointSnap{
+		Store:               l.Store,
+		Version:             l.Version,
+		Mode:                mode,
+		RawPrice:            price,
+		Price:               point,
+		Deviation:           deviation,
+		DeviationPercentage: deviation / price * 100,
+	}, nil
+}
+
+// neighbours returns the highest point at or below minor and the lowest
+// point at or above it, in minor units
+func (l *PricePointLadder) neighbours(minor float64) (below, above int64, hasBelow, hasAbove bool) {
+	for _, r := range l.Ranges {
+		first, last, step := r.First.Amount, r.Last.Amount, r.Step.Amount
+		switch {
+		case minor > float64(last):
+			below, hasBelow = last, true
+		case minor < float64(first):
+			return below, first, hasBelow, true
+		default:
+			below = first + int64(math.Floor((minor-float64(first))/float64(step)))*step
+			if float64(below) == minor {
+				return below, below, true, true
+			}
+			return below, below + step, true, true
+		}
+	}
+	return below, 0, hasBelow, false
+}
+
+// SnapPrice snaps a price in a currency to a store's price point
+// Returns a NO_DATA error when no ladder is loaded for the store and currency
+func SnapPrice(store, currency string, price float64, mode SnapMode) (*PricePointSnap, error) {
+	ladder, ok := PricePoints(store, currency)
+	if !ok {
+		msg := fmt.Sprintf("no %s price points for currency %s", store, currency)
+		if _, loaded := pricePointStoreFor(store); !loaded {
+			msg = fmt.Sprintf("no %s price points loaded, generate data/pricepoints.json from store exports or use LoadPricePoints", store)
+		}
+		return nil, NewPPPError(ErrCodeNoData, msg, ErrNoData).
+			WithContext("store", store).
+			WithContext("currency", currency)
+	}
+	return ladder.Snap(price, mode)
+}
@@ -0,0 +1,224 @@
+package ppp
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+// loadTestPricePoints loads the price point fixture, which stands in for
+// ladders generated from store exports
+func loadTestPricePoints(t *testing.T) {
+	t.Helper()
+	f, err := os.Open("testdata/pricepoints.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := LoadPricePoints(f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPricePoints(t *testing.T) {
+	// Embedded ladders must be generated from store exports
+	embedded, err := parsePricePoints(pricePointData)
+	if err != nil {
+		t.Fatalf("Invalid embedded price points: %v", err)
+	}
+	for store, s := range embedded {
+		if s.version == "" || s.source == "" || s.retrieved == "" {
+			t.Errorf("Expected version, source and retrieval date for %s", store)
+		}
+	}
+
+	loadTestPricePoints(t)
+	for _, store := range []string{StoreAppStore, StoreGooglePlay} {
+		currencies := PricePointCurrencies(store)
+		if len(currencies) == 0 {
+			t.Fatalf("Expected ladders for %s", store)
+		}
+		for _, currency := range currencies {
+			ladder, ok := PricePoints(store, currency)
+			if !ok || ladder.Len() == 0 || ladder.Version != "test" || ladder.Retrieved != "2025-06-12" {
+				t.Fatalf("Expected a versioned %s ladder for %s, got %+v", store, currency, ladder)
+			}
+			for i := 1; i < len(ladder.Ranges); i++ {
+				if ladder.Ranges[i].First.Amount <= ladder.Ranges[i-1].Last.Amount {
+					t.Fatalf("Expected %s %s ranges in ascending order at %d", store, currency, i)
+				}
+			}
+		}
+	}
+
+	ladder, ok := PricePoints("AppStore", "usd")
+	if !ok {
+		t.Fatal("Expected case-insensitive store and currency")
+	}
+	points := ladder.Points()
+	if len(points) != ladder.Len() {
+		t.Errorf("Expected %d points, got %d", ladder.Len(), len(points))
+	}
+	if first, last := points[0].Decimal(), points[len(points)-1].Decimal(); first != "0.29" || last != "9999.99" {
+		t.Errorf("Expected USD ladder from 0.29 to 9999.99, got %s to %s", first, last)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].Amount <= points[i-1].Amount {
+			t.Fatalf("Expected USD points in ascending order at %d", i)
+		}
+	}
+
+	// Ladders are copies
+	ladder.Ranges[0].First.Amount = 1
+	if again, _ := PricePoints(StoreAppStore, "USD"); again.Ranges[0].First.Amount != 29 {
+		t.Error("Expected PricePoints to return a copy")
+	}
+
+	if _, ok := PricePoints("steam", "USD"); ok {
+		t.Error("Expected no ladder for an unknown store")
+	}
+	if _, ok := PricePoints(StoreAppStore, "XXX"); ok {
+		t.Error("Expected no ladder for an unknown currency")
+	}
+
+	for _, data := range []string{`{"appstore":`, `{"appstore":{"currencies":{"USD":[["1.00","0.50","0.01"]]}}}`, `{"appstore":{"currencies":{"USD":[["0x10","1.00","0.01"]]}}}`, `{"appstore":{"currencies":{"USD":[["0.29","1.00","0.10"]]}}}`} {
+		if err := LoadPricePoints(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected LoadPricePoints(%s) to fail", data)
+		}
+	}
+	if _, ok := PricePoints(StoreAppStore, "USD"); !ok {
+		t.Error("Expected invalid data to leave the loaded ladders alone")
+	}
+}
+
+func TestEmbeddedPricePoints(t *testing.T) {
+	// Read the embedded data directly, other tests load the fixture
+	embedded, err := parsePricePoints(pricePointData)
+	if err != nil {
+		t.Fatalf("Invalid embedded price points: %v", err)
+	}
+	if len(embedded) == 0 {
+		t.Skip("data/pricepoints.json has not been generated from store exports yet, see internal/pricepointgen")
+	}
+	for _, store := range []string{StoreAppStore, StoreGooglePlay} {
+		s, ok := embedded[store]
+		if !ok {
+			t.Errorf("Expected embedded %s price points", store)
+			continue
+		}
+		if s.version == "" || s.retrieved == "" {
+			t.Errorf("Expected a version and retrieval date for %s", store)
+		}
+		for _, currency := range []string{"USD", "EUR", "TRY", "INR"} {
+			if len(s.ladders[currency]) == 0 {
+				t.Errorf("Expected an embedded %s ladder for %s", store, currency)
+			}
+		}
+	}
+}
+
+func TestSnapPrice(t *testing.T) {
+	loadTestPricePoints(t)
+
+	tests := []struct {
+		name     string
+		store    string
+		currency string
+		price    float64
+		mode     SnapMode
+		want     string
+	}{
+		{"exact point", StoreAppStore, "USD", 4.99, SnapUp, "4.99"},
+		{"nearest below", StoreAppStore, "USD", 4.92, SnapNearest, "4.89"},
+		{"nearest above", StoreAppStore, "USD", 4.96, SnapNearest, "4.99"},
+		{"nearest tie goes up", StoreAppStore, "USD", 4.94, SnapNearest, "4.99"},
+		{"up", StoreAppStore, "USD", 4.90, SnapUp, "4.99"},
+		{"down", StoreAppStore, "USD", 4.98, SnapDown, "4.89"},
+		{"segment boundary", StoreAppStore, "USD", 10.1, SnapNearest, "9.99"},
+		{"below ladder", StoreAppStore, "USD", 0.05, SnapDown, "0.29"},
+		{"above ladder", StoreAppStore, "USD", 20000, SnapUp, "9999.99"},
+		{"local currency", StoreAppStore, "TRY", 115.5, SnapNearest, "114.99"},
+		{"google play range", StoreGooglePlay, "USD", 5.234, SnapNearest, "5.23"},
+		{"three decimals", StoreAppStore, "KWD", 1.15, SnapUp, "1.199"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap, err := SnapPrice(tt.store, tt.currency, tt.price, tt.mode)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if snap.Price.Decimal() != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, snap.Price.Decimal())
+			}
+			if snap.RawPrice != tt.price || snap.Store != tt.store || snap.Mode != tt.mode {
+				t.Errorf("Unexpected snap metadata: %+v", snap)
+			}
+			deviation := snap.Price.Float64() - tt.price
+			if math.Abs(snap.Deviation-deviation) > 1e-9 {
+				t.Errorf("Expected deviation %v, got %v", deviation, snap.Deviation)
+			}
+			if math.Abs(snap.DeviationPercentage-deviation/tt.price*100) > 1e-9 {
+				t.Errorf("Expected deviation percentage %v, got %v", deviation/tt.price*100, snap.DeviationPercentage)
+			}
+		})
+	}
+
+	if _, err := SnapPrice("steam", "USD", 5, SnapNearest); !IsNoDataError(err) || !strings.Contains(err.Error(), "LoadPricePoints") {
+		t.Errorf("Expected NO_DATA pointing to LoadPricePoints for an unknown store, got %v", err)
+	}
+	if _, err := SnapPrice(StoreAppStore, "XXX", 5, SnapNearest); !IsNoDataError(err) {
+		t.Errorf("Expected NO_DATA for an unknown currency, got %v", err)
+	}
+	if _, err := SnapPrice(StoreAppStore, "USD", -1, SnapNearest); err == nil {
+		t.Error("Expected an error for a negative price")
+	}
+}
+
+func TestRecommendPricePoint(t *testing.T) {
+	loadTestPricePoints(t)
+	client, _, _ := newPriceBookClient(t, map[string]float64{"TR": 11.55})
+	ctx := context.Background()
+
+	raw, err := client.Recommend(ctx, 10, "USD", "TR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	engine := NewRecommendationEngine(client)
+	for _, mode := range []SnapMode{SnapNearest, SnapUp, SnapDown} {
+		engine.SetPricePointSnapping(StoreGooglePlay, mode)
+		rec, err := engine.RecommendPricePoint(ctx, 10, "USD", "TR")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want, err := SnapPrice(StoreGooglePlay, "TRY", raw.RecommendedPrice, mode)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if rec.PricePoint == nil || rec.PricePoint.Price != want.Price || rec.PricePoint.Store != StoreGooglePlay {
+			t.Fatalf("Expected %s price point %s, got %+v", mode, want.Price.Decimal(), rec.PricePoint)
+		}
+		if rec.RecommendedPrice != want.Price.Float64() {
+			t.Errorf("Expected recommended price %v, got %v", want.Price.Float64(), rec.RecommendedPrice)
+		}
+		if rec.PricePoint.RawPrice != raw.RecommendedPrice {
+			t.Errorf("Expected raw price %v, got %v", raw.RecommendedPrice, rec.PricePoint.RawPrice)
+		}
+		wantDiscount := 100 - (100-raw.DiscountPercentage)*rec.RecommendedPrice/raw.RecommendedPrice
+		if math.Abs(rec.DiscountPercentage-wantDiscount) > 1e-9 {
+			t.Errorf("Expected discount %v, got %v", wantDiscount, rec.DiscountPercentage)
+		}
+	}
+
+	engine.SetPricePointSnapping("steam", SnapNearest)
+	if _, err := engine.RecommendPricePoint(ctx, 10, "USD", "TR"); !IsNoDataError(err) {
+		t.Errorf("Expected NO_DATA for an unknown store, got %v", err)
+	}
+	if _, err := engine.RecommendPricePoint(ctx, 10, "USD", "XX"); err == nil {
+		t.Error("Expected an error for an invalid country")
+	}
+}
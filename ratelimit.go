@@ -0,0 +1,290 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimit configures a token bucket
+type rateLimit struct {
+	perSecond float64
+	burst     int
+}
+
+// breakerConfig configures a circuit breaker
+type breakerConfig struct {
+	threshold int
+	cooldown  time.Duration
+	probes    int
+}
+
+// tokenBucket is a token-bucket rate limiter
+type tokenBucket struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+// newTokenBucket creates a full token bucket
+func newTokenBucket(limit rateLimit) *tokenBucket {
+	burst := float64(limit.burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		perSecond: limit.perSecond,
+		burst:     burst,
+		tokens:    burst,
+		last:      time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	// Refill for the time elapsed since the last reservation
+	b.tokens += now.Sub(b.last).Seconds() * b.perSecond
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.perSecond * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+}
+
+// wait blocks until a token is available or the context ends
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+	
+	// Fail fast if the token arrives after the caller's deadline
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		b.cancel()
+		return NewPPPError(
+			ErrCodeRateLimit,
+			"rate limit wait exceeds request deadline",
+			ErrRateLimited,
+		).WithContext("wait", delay.String())
+	}
+	
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Circuit breaker states
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops calling an upstream after consecutive failures
+type circuitBreaker struct {
+	mu       sync.Mutex
+	config   breakerConfig
+	state    int
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// allow reports whether a request may be sent
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	switch b.state {
+	case breakerOpen:
+		if now.Sub(b.openedAt) < b.config.cooldown {
+			return false
+		}
+		// Cooldown elapsed, let probes through
+		b.state = breakerHalfOpen
+		b.probes = 0
+		fallthrough
+	case breakerHalfOpen:
+		if b.probes >= b.config.probes {
+			return false
+		}
+		b.probes++
+		return true
+	default:
+		return true
+	}
+}
+
+// release gives back a half-open probe slot that was not used
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// record updates the breaker with the outcome of a request
+func (b *circuitBreaker) record(now time.Time, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.config.threshold {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+}
+
+// upstreamGuard holds rate limiters and circuit breakers per upstream host
+type upstreamGuard struct {
+	mu           sync.Mutex
+	defaultLimit *rateLimit
+	hostLimits   map[string]rateLimit
+	breaker      *breakerConfig
+	limiters     map[string]*tokenBucket
+	breakers     map[string]*circuitBreaker
+}
+
+// newUpstreamGuard creates a guard for the client's configuration
+func newUpstreamGuard(c *Client) *upstreamGuard {
+	g := &upstreamGuard{
+		hostLimits: make(map[string]rateLimit),
+		breaker:    c.breaker,
+		limiters:   make(map[string]*tokenBucket),
+		breakers:   make(map[string]*circuitBreaker),
+	}
+	for host, limit := range c.rateLimits {
+		if host == "" {
+			limit := limit
+			g.defaultLimit = &limit
+		} else {
+			g.hostLimits[host] = limit
+		}
+	}
+	return g
+}
+
+// limiterFor returns the rate limiter for a host, or nil if it is unlimited
+func (g *upstreamGuard) limiterFor(host string) *tokenBucket {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	
+	if limiter, ok := g.limiters[host]; ok {
+		return limiter
+	}
+	
+	limit, ok := g.hostLimits[host]
+	if !ok {
+		if g.defaultLimit == nil {
+			return nil
+		}
+		limit = *g.defaultLimit
+	}
+	if limit.perSecond <= 0 {
+		g.limiters[host] = nil
+		return nil
+	}
+	
+	limiter := newTokenBucket(limit)
+	g.limiters[host] = limiter
+	return limiter
+}
+
+// breakerFor returns the circuit breaker for a host, or nil if disabled
+func (g *upstreamGuard) breakerFor(host string) *circuitBreaker {
+	if g.breaker == nil {
+		return nil
+	}
+	
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	
+	breaker, ok := g.breakers[host]
+	if !ok {
+		breaker = &circuitBreaker{config: *g.breaker}
+		g.breakers[host] = breaker
+	}
+	return breaker
+}
+
+// guardedTransport applies an upstreamGuard to every outgoing request
+type guardedTransport struct {
+	guard *upstreamGuard
+	base  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	
+	breaker := t.guard.breakerFor(host)
+	if breaker != nil && !breaker.allow(time.Now()) {
+		return nil, NewPPPError(
+			ErrCodeAPIError,
+			"circuit breaker open for "+host,
+			ErrCircuitOpen,
+		).WithContext("host", host)
+	}
+	
+	if limiter := t.guard.limiterFor(host); limiter != nil {
+		if err := limiter.wait(req.Context()); err != nil {
+			if breaker != nil {
+				// Not an upstream failure, just give back the probe slot
+				breaker.release()
+			}
+			return nil, err
+		}
+	}
+	
+	resp, err := t.base.RoundTrip(req)
+	
+	if breaker != nil {
+		if errors.Is(err, context.Canceled) {
+			breaker.release()
+		} else {
+			failed := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+			breaker.record(time.Now(), !failed)
+		}
+	}
+	
+	return resp, err
+}
+
+// isGuardError reports whether err was produced by an upstream guard
+// Such errors are final and must not be retried
+func isGuardError(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrCircuitOpen)
+}
@@ -0,0 +1,128 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var requests, healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `[{"page":1},[{"country":{"id":"TR","value":"Turkiye"},"date":"2023","value":11.55}]]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithoutCache(), WithWorldBankURL(server.URL), WithCircuitBreaker(2, 50*time.Millisecond))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPPP(ctx, "TR"); err == nil {
+			t.Fatal("Expected upstream failure")
+		}
+	}
+
+	_, err := client.GetPPP(ctx, "TR")
+	if !errors.Is(err, ErrCircuitOpen) || !IsAPIError(err) {
+		t.Fatalf("Expected open circuit error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected open circuit to short-circuit, upstream saw %d requests", got)
+	}
+
+	// After the cooldown a probe is let through and closes the circuit
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.GetPPP(ctx, "TR"); err != nil {
+		t.Fatalf("Expected probe to succeed, got %v", err)
+	}
+	if _, err := client.GetPPP(ctx, "TR"); err != nil {
+		t.Fatalf("Expected closed circuit, got %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"date":"2024-05-01","usd":{"try":32.5}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithoutCache(), WithCurrencyURL(server.URL), WithRateLimit(1, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetExchangeRate(ctx, "USD", "TRY"); err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+
+	_, err := client.GetExchangeRate(ctx, "USD", "TRY")
+	if !IsRateLimitError(err) {
+		t.Fatalf("Expected rate limit error, got %v", err)
+	}
+}
+
+func TestRateLimitValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"date":"2024-05-01","usd":{"try":32.5}}`)
+	}))
+	defer server.Close()
+
+	for _, perSecond := range []float64{0, -1} {
+		client := NewClient(WithoutCache(), WithCurrencyURL(server.URL), WithRateLimit(perSecond, 0))
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		for i := 0; i < 3; i++ {
+			if _, err := client.GetExchangeRate(ctx, "USD", "TRY"); err != nil {
+				t.Errorf("%v requests per second: request %d failed: %v", perSecond, i, err)
+			}
+		}
+		cancel()
+	}
+
+	// A host limit of 0 lifts the default limit for that host
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := NewClient(WithoutCache(), WithCurrencyURL(server.URL), WithRateLimit(1, 1), WithHostRateLimit(host, 0, 1))
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetExchangeRate(context.Background(), "USD", "TRY"); err != nil {
+			t.Errorf("Request %d failed: %v", i, err)
+		}
+	}
+
+	// A burst below 1 holds one token
+	bucket := newTokenBucket(rateLimit{perSecond: 10, burst: -5})
+	if wait := bucket.reserve(bucket.last); wait != 0 {
+		t.Errorf("First token wait = %v, want 0", wait)
+	}
+	if wait := bucket.reserve(bucket.last); wait != 100*time.Millisecond {
+		t.Errorf("Second token wait = %v, want 100ms", wait)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(rateLimit{perSecond: 10, burst: 2})
+	now := bucket.last
+
+	if wait := bucket.reserve(now); wait != 0 {
+		t.Errorf("First token wait = %v, want 0", wait)
+	}
+	if wait := bucket.reserve(now); wait != 0 {
+		t.Errorf("Burst token wait = %v, want 0", wait)
+	}
+	if wait := bucket.reserve(now); wait != 100*time.Millisecond {
+		t.Errorf("Third token wait = %v, want 100ms", wait)
+	}
+	if wait := bucket.reserve(now.Add(time.Second)); wait != 0 {
+		t.Errorf("Token after refill wait = %v, want 0", wait)
+	}
+}
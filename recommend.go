@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/big"
 )
 
 // PricingTier represents different pricing tiers based on PPP
@@ -26,6 +27,8 @@ var StandardPricingTiers = []PricingTier{
 type RecommendationEngine struct {
 	client       *Client
 	pricingTiers []PricingTier
+	snapStore    string
+	snapMode     SnapMode
 }
 
 // NewRecommendationEngine creates a new recommendation engine
@@ -33,6 +36,8 @@ func NewRecommendationEngine(client *Client) *RecommendationEngine {
 	return &RecommendationEngine{
 		client:       client,
 		pricingTiers: StandardPricingTiers,
+		snapStore:    StoreAppStore,
+		snapMode:     SnapNearest,
 	}
 }
 
@@ -41,6 +46,49 @@ func (r *RecommendationEngine) SetPricingTiers(tiers []PricingTier) {
 	r.pricingTiers = tiers
 }
 
+// SetPricePointSnapping sets the store and mode used by RecommendPricePoint
+// The default is the App Store, snapping to the nearest price point
+func (r *RecommendationEngine) SetPricePointSnapping(store string, mode SnapMode) {
+	r.snapStore = store
+	r.snapMode = mode
+}
+
+// RecommendPricePoint recommends a PPP price snapped to a store price point
+// RecommendedPrice is the price point; the raw PPP price and the deviation
+// from it are reported in PricePoint
+func (r *RecommendationEngine) RecommendPricePoint(ctx context.Context, price float64, fromCurrency, toCountry string) (*PriceRecommendation, error) {
+	// Validate inputs
+	if err := ValidateAmount(price); err != nil {
+		return nil, err
+	}
+	
+	if err := ValidateCurrencyCode(fromCurrency); err != nil {
+		return nil, err
+	}
+	
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	rec, err := r.client.Recommend(ctx, price, fromCurrency, toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	snap, err := SnapPrice(r.snapStore, rec.TargetCurrency, rec.RecommendedPrice, r.snapMode)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Keep the discount consistent with the snapped price
+	rec.DiscountPercentage = 100 - (100-rec.DiscountPercentage)*snap.Price.Float64()/rec.RecommendedPrice
+	rec.RecommendedPrice = snap.Price.Float64()
+	rec.PricePoint = snap
+	
+	return rec, nil
+}
+
 // RecommendWithStrategy provides strategic price recommendation
 func (r *RecommendationEngine) RecommendWithStrategy(ctx context.Context, price float64, fromCurrency, toCountry string) (*PriceRecommendation, error) {
 	// Validate inputs
@@ -52,7 +100,8 @@ func (r *RecommendationEngine) RecommendWithStrategy(ctx context.Context, price
 		return nil, err
 	}
 	
-	if err := ValidateCountryCode(toCountry); err != nil {
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
 		return nil, err
 	}
 	
@@ -83,6 +132,9 @@ func (r *RecommendationEngine) getTierForPPP(pppFactor float64) *PricingTier {
 	return nil
 }
 
+// annualDiscountPerMille is the annual plan discount in 1/1000 (~2 months free)
+const annualDiscountPerMille = 167
+
 // RecommendSaaS provides SaaS-specific pricing recommendation
 func (r *RecommendationEngine) RecommendSaaS(ctx context.Context, monthlyPrice float64, fromCurrency, toCountry string) (*SaaSPricing, error) {
 	// Get base recommendation
@@ -92,7 +144,7 @@ func (r *RecommendationEngine) RecommendSaaS(ctx context.Context, monthlyPrice f
 	}
 	
 	// Calculate annual pricing with discount
-	annualDiscount := 0.167 // ~2 months free
+	annualDiscount := float64(annualDiscountPerMille) / 1000
 	annualPrice := rec.RecommendedPrice * 12 * (1 - annualDiscount)
 	
 	return &SaaSPricing{
@@ -115,92 +167,77 @@ type SaaSPricing struct {
 	PPPAdjusted        bool
 }
 
-// RoundPrice rounds price to appropriate decimal places based on currency
-func RoundPrice(price float64, currency string) float64 {
-	// Currencies that typically don't use decimals
-	noDecimalCurrencies := map[string]bool{
-		"JPY": true,
-		"KRW": true,
-		"IDR": true,
-		"VND": true,
-		"CLP": true,
-		"PYG": true,
-		"RWF": true,
-		"XAF": true,
-		"XOF": true,
-		"XPF": true,
+// RecommendSaaSMoney is RecommendSaaS with exact Money amounts
+// Annual prices and savings are computed in minor units, so they add up exactly
+func (r *RecommendationEngine) RecommendSaaSMoney(ctx context.Context, monthlyPrice Money, toCountry string) (*SaaSMoneyPricing, error) {
+	rec, err := r.RecommendWithStrategy(ctx, monthlyPrice.Float64(), monthlyPrice.Currency, toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	monthly, err := MoneyFromFloat(rec.RecommendedPrice, rec.TargetCurrency)
+	if err != nil {
+		return nil, err
 	}
 	
-	if noDecimalCurrencies[currency] {
-		return math.Round(price)
+	yearly, err := monthly.Mul(12)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Round to 2 decimal places for most currencies
-	return math.Round(price*100) / 100
+	annual, err := yearly.MulRatio(1000-annualDiscountPerMille, 1000, RoundHalfUp)
+	if err != nil {
+		return nil, err
+	}
+	
+	savings, err := yearly.Sub(annual)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &SaaSMoneyPricing{
+		Monthly:            monthly,
+		Annual:             annual,
+		AnnualSavings:      savings,
+		DiscountPercentage: rec.DiscountPercentage,
+		PPPAdjusted:        true,
+	}, nil
+}
+
+// SaaSMoneyPricing represents SaaS-specific pricing with exact amounts
+type SaaSMoneyPricing struct {
+	Monthly            Money   `json:"monthly"`
+	Annual             Money   `json:"annual"`
+	AnnualSavings      Money   `json:"annual_savings"`
+	DiscountPercentage float64 `json:"discount_percentage"`
+	PPPAdjusted        bool    `json:"ppp_adjusted"`
+}
+
+// RoundPrice rounds price to appropriate decimal places based on currency
+// Uses the currency's cash digits from the ISO 4217 registry (2 if unknown)
+func RoundPrice(price float64, currency string) float64 {
+	scale := math.Pow10(currencyCashDigits(currency))
+	return math.Round(price*scale) / scale
 }
 
 // FormatPrice formats price according to currency conventions
 func FormatPrice(price float64, currency string) string {
+	digits := currencyCashDigits(currency)
 	rounded := RoundPrice(price, currency)
 	
-	currencySymbols := map[string]string{
-		"USD": "$",
-		"EUR": "€",
-		"GBP": "£",
-		"JPY": "¥",
-		"CNY": "¥",
-		"INR": "₹",
-		"TRY": "₺",
-		"RUB": "₽",
-		"KRW": "₩",
-		"BRL": "R$",
-		"MXN": "$",
-		"CAD": "C$",
-		"AUD": "A$",
-		"CHF": "CHF",
-		"SEK": "kr",
-		"NOK": "kr",
-		"DKK": "kr",
-		"PLN": "zł",
-		"CZK": "Kč",
-		"HUF": "Ft",
-		"ILS": "₪",
-		"AED": "د.إ",
-		"SAR": "ر.س",
-		"ZAR": "R",
-		"NGN": "₦",
-		"EGP": "E£",
-		"PKR": "₨",
-		"BDT": "৳",
-		"VND": "₫",
-		"THB": "฿",
-		"MYR": "RM",
-		"SGD": "S$",
-		"PHP": "₱",
-		"IDR": "Rp",
-		"NZD": "NZ$",
-		"ARS": "$",
-		"CLP": "$",
-		"COP": "$",
-		"PEN": "S/",
-	}
-	
-	symbol, ok := currencySymbols[currency]
-	if !ok {
-		symbol = currency + " "
+	symbol := currency + " "
+	if c, ok := LookupCurrency(currency); ok && c.Symbol != "" {
+		symbol = c.Symbol
 	}
 	
 	// Format based on currency conventions
 	switch currency {
 	case "EUR", "RUB", "PLN", "CZK", "HUF":
 		// Symbol after amount for these currencies
-		return fmt.Sprintf("%.2f %s", rounded, symbol)
-	case "JPY", "KRW", "IDR", "VND", "CLP":
-		// No decimals for these currencies
-		return fmt.Sprintf("%s%.0f", symbol, rounded)
+		return fmt.Sprintf("%.*f %s", digits, rounded, symbol)
 	default:
 		// Symbol before amount (most common)
-		return fmt.Sprintf("%s%.2f", symbol, rounded)
+		return fmt.Sprintf("%s%.*f", symbol, digits, rounded)
 	}
 }
 
@@ -211,7 +248,8 @@ func CalculateMarketBasket(ctx context.Context, client *Client, items map[string
 		return nil, err
 	}
 	
-	if err := ValidateCountryCode(toCountry); err != nil {
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
 		return nil, err
 	}
 	
@@ -251,4 +289,76 @@ func CalculateMarketBasket(ctx context.Context, client *Client, items map[string
 	}
 	
 	return results, nil
-}
\ No newline at end of file
+}
+
+// CalculateMarketBasketMoney is CalculateMarketBasket with exact Money amounts
+// All items must share one currency; adjusted prices are in that currency
+func CalculateMarketBasketMoney(ctx context.Context, client *Client, items map[string]Money, toCountry string) (map[string]Money, error) {
+	toCountry, err := NormalizeCountryCode(toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	if len(items) == 0 {
+		return nil, NewPPPError(
+			ErrCodeInvalidInput,
+			"no items provided",
+			nil,
+		)
+	}
+	
+	// All items must be priced in the same currency
+	var fromCurrency string
+	for item, price := range items {
+		if err := ValidateAmount(price.Float64()); err != nil {
+			return nil, fmt.Errorf("invalid price for item %s: %w", item, err)
+		}
+		if fromCurrency == "" {
+			fromCurrency = price.Currency
+		} else if price.Currency != fromCurrency {
+			return nil, fmt.Errorf("invalid price for item %s: %w", item, price.sameCurrency(Money{Currency: fromCurrency}))
+		}
+	}
+	
+	if err := ValidateCurrencyCode(fromCurrency); err != nil {
+		return nil, err
+	}
+	
+	// Get PPP data once
+	ppp, err := client.GetPPP(ctx, toCountry)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Get exchange rate once
+	toCurrency := client.getCurrencyForCountry(toCountry)
+	rate, err := client.GetExchangeRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Apply PPP adjustment: price * factor / rate, exactly
+	pppFactor, err := ratFromFloat(ppp.Factor)
+	if err != nil {
+		return nil, err
+	}
+	marketRate, err := ratFromFloat(rate.Rate)
+	if err != nil {
+		return nil, err
+	}
+	if marketRate.Sign() <= 0 {
+		return nil, NewPPPError(ErrCodeAPIError, "exchange rate must be positive", ErrNoData)
+	}
+	adjustment := new(big.Rat).Quo(pppFactor, marketRate)
+	
+	results := make(map[string]Money, len(items))
+	for item, price := range items {
+		adjusted := new(big.Rat).SetInt64(price.Amount)
+		results[item], err = moneyFromRat(adjusted.Mul(adjusted, adjustment), price.Currency, RoundHalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price for item %s: %w", item, err)
+		}
+	}
+	
+	return results, nil
+}
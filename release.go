@@ -0,0 +1,66 @@
+package ppp
+
+import (
+	"time"
+)
+
+// ReleaseSchedule describes when the World Bank publishes a new PPP vintage
+// PPP data for year Y appears in the release made in year Y+1
+type ReleaseSchedule struct {
+	Month time.Month
+	Day   int
+	// Window after the release date during which stale PPP entries are
+	// refreshed daily, because publication can slip by several weeks
+	Window time.Duration
+}
+
+// DefaultPPPRelease follows the World Development Indicators summer update
+var DefaultPPPRelease = ReleaseSchedule{
+	Month:  time.July,
+	Day:    1,
+	Window: 60 * 24 * time.Hour,
+}
+
+// releaseIn returns the release date in a given year
+func (s ReleaseSchedule) releaseIn(year int) time.Time {
+	return time.Date(year, s.Month, s.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// Previous returns the last release date at or before t
+func (s ReleaseSchedule) Previous(t time.Time) time.Time {
+	release := s.releaseIn(t.Year())
+	if release.After(t) {
+		release = s.releaseIn(t.Year() - 1)
+	}
+	return release
+}
+
+// Next returns the first release date after t
+func (s ReleaseSchedule) Next(t time.Time) time.Time {
+	release := s.releaseIn(t.Year())
+	if !release.After(t) {
+		release = s.releaseIn(t.Year() + 1)
+	}
+	return release
+}
+
+// LatestYear returns the most recent data year expected to be published at t
+// Example: with a July release, LatestYear(2024-03-01) returns 2022
+func (s ReleaseSchedule) LatestYear(t time.Time) int {
+	return s.Previous(t).Year() - 1
+}
+
+// TTL returns how long PPP data fetched at now should be cached
+// Data is kept until the next release, except right after a release when
+// the newest vintage has not shown up yet
+func (s ReleaseSchedule) TTL(now time.Time, data *PPPData) time.Duration {
+	untilNext := s.Next(now).Sub(now)
+	
+	inWindow := now.Before(s.Previous(now).Add(s.Window))
+	upToDate := data != nil && data.Year >= s.LatestYear(now)
+	if inWindow && !upToDate && untilNext > 24*time.Hour {
+		return 24 * time.Hour
+	}
+	
+	return untilNext
+}
@@ -0,0 +1,96 @@
+package report
+
+import "strings"
+
+// headerTranslations holds column headers by language
+var headerTranslations = map[string]map[string]string{
+	"en": {
+		"rank": "Rank", "country": "Country", "country_code": "Country Code", "country_name": "Country Name",
+		"year": "Year", "start_year": "Start Year", "end_year": "End Year",
+		"factor": "PPP Factor", "percent_of_us": "% of US", "average": "Average Factor",
+		"trend": "Trend", "volatility": "Volatility", "data_points": "Data Points",
+		"price": "Price", "currency": "Currency", "source": "Source",
+	},
+	"de": {
+		"rank": "Rang", "country": "Land", "country_code": "Ländercode", "country_name": "Landesname",
+		"year": "Jahr", "start_year": "Startjahr", "end_year": "Endjahr",
+		"factor": "KKP-Faktor", "percent_of_us": "% der USA", "average": "Durchschnittsfaktor",
+		"trend": "Trend", "volatility": "Volatilität", "data_points": "Datenpunkte",
+		"price": "Preis", "currency": "Währung", "source": "Quelle",
+	},
+	"es": {
+		"rank": "Posición", "country": "País", "country_code": "Código de país", "country_name": "Nombre del país",
+		"year": "Año", "start_year": "Año inicial", "end_year": "Año final",
+		"factor": "Factor PPA", "percent_of_us": "% de EE. UU.", "average": "Factor medio",
+		"trend": "Tendencia", "volatility": "Volatilidad", "data_points": "Datos",
+		"price": "Precio", "currency": "Moneda", "source": "Fuente",
+	},
+	"fr": {
+		"rank": "Rang", "country": "Pays", "country_code": "Code pays", "country_name": "Nom du pays",
+		"year": "Année", "start_year": "Année de début", "end_year": "Année de fin",
+		"factor": "Facteur PPA", "percent_of_us": "% des États-Unis", "average": "Facteur moyen",
+		"trend": "Tendance", "volatility": "Volatilité", "data_points": "Points de données",
+		"price": "Prix", "currency": "Devise", "source": "Source",
+	},
+	"it": {
+		"rank": "Posizione", "country": "Paese", "country_code": "Codice paese", "country_name": "Nome del paese",
+		"year": "Anno", "start_year": "Anno iniziale", "end_year": "Anno finale",
+		"factor": "Fattore PPA", "percent_of_us": "% degli USA", "average": "Fattore medio",
+		"trend": "Tendenza", "volatility": "Volatilità", "data_points": "Punti dati",
+		"price": "Prezzo", "currency": "Valuta", "source": "Fonte",
+	},
+	"pt": {
+		"rank": "Posição", "country": "País", "country_code": "Código do país", "country_name": "Nome do país",
+		"year": "Ano", "start_year": "Ano inicial", "end_year": "Ano final",
+		"factor": "Fator PPC", "percent_of_us": "% dos EUA", "average": "Fator médio",
+		"trend": "Tendência", "volatility": "Volatilidade", "data_points": "Pontos de dados",
+		"price": "Preço", "currency": "Moeda", "source": "Fonte",
+	},
+	"nl": {
+		"rank": "Rang", "country": "Land", "country_code": "Landcode", "country_name": "Landnaam",
+		"year": "Jaar", "start_year": "Beginjaar", "end_year": "Eindjaar",
+		"factor": "KKP-factor", "percent_of_us": "% van VS", "average": "Gemiddelde factor",
+		"trend": "Trend", "volatility": "Volatiliteit", "data_points": "Datapunten",
+		"price": "Prijs", "currency": "Valuta", "source": "Bron",
+	},
+	"tr": {
+		"rank": "Sıra", "country": "Ülke", "country_code": "Ülke Kodu", "country_name": "Ülke Adı",
+		"year": "Yıl", "start_year": "Başlangıç Yılı", "end_year": "Bitiş Yılı",
+		"factor": "SGP Faktörü", "percent_of_us": "ABD'ye Göre %", "average": "Ortalama Faktör",
+		"trend": "Eğilim", "volatility": "Oynaklık", "data_points": "Veri Noktası",
+		"price": "Fiyat", "currency": "Para Birimi", "source": "Kaynak",
+	},
+	"ja": {
+		"rank": "順位", "country": "国コード", "country_code": "国コード", "country_name": "国名",
+		"year": "年", "start_year": "開始年", "end_year": "終了年",
+		"factor": "PPP係数", "percent_of_us": "米国比 (%)", "average": "平均係数",
+		"trend": "傾向", "volatility": "変動率", "data_points": "データ数",
+		"price": "価格", "currency": "通貨", "source": "出典",
+	},
+	"zh": {
+		"rank": "排名", "country": "国家代码", "country_code": "国家代码", "country_name": "国家名称",
+		"year": "年份", "start_year": "起始年份", "end_year": "结束年份",
+		"factor": "PPP 系数", "percent_of_us": "相对美国 (%)", "average": "平均系数",
+		"trend": "趋势", "volatility": "波动率", "data_points": "数据点",
+		"price": "价格", "currency": "货币", "source": "来源",
+	},
+}
+
+// HeaderLanguages lists the languages headers are translated to
+var HeaderLanguages = []string{"de", "en", "es", "fr", "it", "ja", "nl", "pt", "tr", "zh"}
+
+// localizedHeader returns the header of a column in a locale's language,
+// falling back to English and then to the column name
+func localizedHeader(name, locale string) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if h, ok := headerTranslations[lang][name]; ok {
+		return h
+	}
+	if h, ok := headerTranslations["en"][name]; ok {
+		return h
+	}
+	return name
+}
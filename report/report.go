@@ -0,0 +1,156 @@
+// Package report renders PPP comparisons, trend analyses, historical series
+// and batch prices as CSV, JSON Lines, Markdown and HTML tables
+//
+// Every table uses the same snake_case column names as the JSON of the ppp
+// types it is built from, and the same number of decimals per column in
+// every format. Headers can be localized with WithLocale
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// Report formats accepted by Write
+const (
+	FormatCSV      = "csv"
+	FormatJSONL    = "jsonl"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the report formats
+var Formats = []string{FormatCSV, FormatJSONL, FormatMarkdown, FormatHTML}
+
+// Table is a report table built from ppp results
+type Table struct {
+	columns []column
+	rows    [][]interface{}
+}
+
+// column is a table column; decimals applies to float64 cells
+type column struct {
+	name     string
+	decimals int
+}
+
+// Columns returns the column names of the table
+func (t *Table) Columns() []string {
+	names := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// config holds the report options
+type config struct {
+	locale  string
+	headers map[string]string
+}
+
+// Option is a functional option for configuring a report
+type Option func(*config)
+
+// WithLocale translates the CSV, Markdown and HTML headers to a locale's
+// language and, in Markdown and HTML, formats numbers with the locale's
+// separators. CSV and JSON Lines numbers stay machine-readable
+// Example: WithLocale("de-DE") heads the factor column "KKP-Faktor"
+func WithLocale(locale string) Option {
+	return func(c *config) {
+		c.locale = locale
+	}
+}
+
+// WithHeaders overrides the CSV, Markdown and HTML headers of columns by
+// column name, taking precedence over WithLocale
+// Example: WithHeaders(map[string]string{"factor": "LCU per intl $"})
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) {
+		c.headers = headers
+	}
+}
+
+// newConfig applies options
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// header returns the header of a column
+func (c *config) header(name string) string {
+	if h, ok := c.headers[name]; ok {
+		return h
+	}
+	if c.locale != "" {
+		return localizedHeader(name, c.locale)
+	}
+	return name
+}
+
+// Write renders a table in one of the Formats
+func (t *Table) Write(w io.Writer, format string, opts ...Option) error {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return t.WriteCSV(w, opts...)
+	case FormatJSONL:
+		return t.WriteJSONL(w, opts...)
+	case FormatMarkdown, "md":
+		return t.WriteMarkdown(w, opts...)
+	case FormatHTML:
+		return t.WriteHTML(w, opts...)
+	default:
+		return ppp.NewPPPError(ppp.ErrCodeInvalidInput, fmt.Sprintf("unknown report format %q", format), nil).
+			WithContext("formats", Formats)
+	}
+}
+
+// isNumber reports whether a cell is numeric
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, float64, ppp.Money:
+		return true
+	}
+	return false
+}
+
+// format returns the text of a cell; fractional numbers use the column
+// decimals, or the currency digits for Money, with the locale's separators
+// if one is given
+func (c column) format(v interface{}, locale string) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		// Years, ranks and counts are never grouped
+		return strconv.Itoa(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ""
+		}
+		if locale != "" {
+			return ppp.FormatNumberLocale(v, c.decimals, locale)
+		}
+		return strconv.FormatFloat(v, 'f', c.decimals, 64)
+	case ppp.Money:
+		if locale != "" {
+			return ppp.FormatNumberLocale(v.Float64(), v.Digits(), locale)
+		}
+		return v.Decimal()
+	default:
+		return ""
+	}
+}
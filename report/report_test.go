@@ -0,0 +1,167 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+func testComparison() *Table {
+	return Comparison([]ppp.CountryComparison{
+		{Country: "TR", CountryName: "Türkiye", Factor: 11.55, PercentOfUS: 1155, Rank: 1},
+		{Country: "DE", CountryName: "Germany", Factor: 0.7512345, PercentOfUS: 75.12345, Rank: 2},
+	})
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testComparison().WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"rank", "country", "country_name", "factor", "percent_of_us"},
+		{"1", "TR", "Türkiye", "11.5500", "1155.00"},
+		{"2", "DE", "Germany", "0.7512", "75.12"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d records, got %v", len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("Record %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+
+	// Localized headers keep machine-readable numbers
+	buf.Reset()
+	if err := testComparison().WriteCSV(&buf, WithLocale("de-DE")); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "Rang,Land,Landesname,KKP-Faktor,% der USA" || lines[1] != "1,TR,Türkiye,11.5500,1155.00" {
+		t.Errorf("Unexpected localized CSV:\n%s", buf.String())
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	table := History([]ppp.PPPData{
+		{CountryCode: "TR", CountryName: "Turkiye", Year: 2023, Factor: 11.55, Source: "World Bank"},
+		{CountryCode: "TR", Year: 2022, Factor: 8.12, Source: "World Bank"},
+	})
+	if err := table.WriteJSONL(&buf, WithLocale("de")); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per row, got %q", buf.String())
+	}
+	if want := `{"country_code":"TR","country_name":"Turkiye","year":2023,"factor":11.5500,"source":"World Bank"}`; lines[0] != want {
+		t.Errorf("Got %s, want %s", lines[0], want)
+	}
+
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatal(err)
+	}
+	if row["country_name"] != "Türkiye" || row["factor"] != 8.12 {
+		t.Errorf("Unexpected row %v", row)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	table := Trend(&ppp.PPPTrendAnalysis{
+		Country: "IN", StartYear: 2015, EndYear: 2023, Average: 21.345678, Trend: "increasing", Volatility: 1.2, DataPoints: 9,
+	}, nil)
+	if err := table.WriteMarkdown(&buf, WithLocale("fr"), WithHeaders(map[string]string{"trend": "Sens | direction"})); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "| Pays | Nom du pays | Année de début | Année de fin | Facteur moyen | Sens \\| direction | Volatilité | Points de données |\n" +
+		"| --- | --- | ---: | ---: | ---: | --- | ---: | ---: |\n" +
+		"| IN | India | 2015 | 2023 | 21,3457 | increasing | 1,2000 | 9 |\n"
+	if buf.String() != want {
+		t.Errorf("Got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	table := Batch(map[string]float64{"TR": 1155.499, "JP": 1234.4, "IN": 123456.7})
+	if err := table.WriteHTML(&buf, WithLocale("en-IN")); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<th>Country</th><th>Country Name</th><th>Price</th><th>Currency</th>",
+		`<tr><td>IN</td><td>India</td><td class="number">1,23,456.70</td><td>INR</td></tr>`,
+		`<tr><td>JP</td><td>Japan</td><td class="number">1,234</td><td>JPY</td></tr>`,
+		`<td class="number">1,155.50</td><td>TRY</td>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "<td>IN</td>") > strings.Index(out, "<td>JP</td>") {
+		t.Error("Expected batch rows sorted by country")
+	}
+
+	// Failed countries have no price
+	buf.Reset()
+	failed := Batch(map[string]float64{"TR": 1155.5, "BR": 0})
+	if err := failed.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<tr><td>BR</td><td>Brazil</td><td></td><td>BRL</td></tr>"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected an empty price for a failed country, got\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := failed.WriteJSONL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"country":"BR","country_name":"Brazil","price":null,"currency":"BRL"}`; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected a null price for a failed country, got\n%s", buf.String())
+	}
+
+	// Cells are escaped
+	buf.Reset()
+	History([]ppp.PPPData{{CountryCode: "TR", Source: "<script>"}}).WriteHTML(&buf)
+	if strings.Contains(buf.String(), "<script>") {
+		t.Errorf("Expected escaped cells, got %s", buf.String())
+	}
+}
+
+func TestWrite(t *testing.T) {
+	for _, format := range append(Formats, "MD") {
+		var buf bytes.Buffer
+		if err := testComparison().Write(&buf, format); err != nil || buf.Len() == 0 {
+			t.Errorf("%s: unexpected error %v", format, err)
+		}
+	}
+
+	if err := testComparison().Write(&bytes.Buffer{}, "xlsx"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	if got := testComparison().Columns(); len(got) != 5 || got[3] != "factor" {
+		t.Errorf("Unexpected columns %v", got)
+	}
+	if localizedHeader("factor", "sv-SE") != "PPP Factor" || localizedHeader("other", "de") != "other" {
+		t.Error("Expected headers to fall back to English, then the column name")
+	}
+	if got := History(nil).Columns(); got[0] != "country_code" || localizedHeader(got[0], "de") != "Ländercode" {
+		t.Errorf("Expected History to start with a localized country_code column, got %v", got)
+	}
+}
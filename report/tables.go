@@ -0,0 +1,119 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// Decimals used for every table, so a factor reads the same in all reports
+const (
+	factorDecimals  = 4
+	percentDecimals = 2
+)
+
+// Comparison builds a table from ppp.Client.ComparePPP results
+// Columns: rank, country, country_name, factor, percent_of_us
+func Comparison(comparisons []ppp.CountryComparison) *Table {
+	t := &Table{columns: []column{
+		{name: "rank"},
+		{name: "country"},
+		{name: "country_name"},
+		{name: "factor", decimals: factorDecimals},
+		{name: "percent_of_us", decimals: percentDecimals},
+	}}
+	for _, c := range comparisons {
+		t.rows = append(t.rows, []interface{}{c.Rank, c.Country, c.CountryName, c.Factor, c.PercentOfUS})
+	}
+	return t
+}
+
+// Trend builds a table from ppp.Client.AnalyzePPPTrend results, one row per
+// analysis
+// Columns: country, country_name, start_year, end_year, average, trend,
+// volatility, data_points
+func Trend(analyses ...*ppp.PPPTrendAnalysis) *Table {
+	t := &Table{columns: []column{
+		{name: "country"},
+		{name: "country_name"},
+		{name: "start_year"},
+		{name: "end_year"},
+		{name: "average", decimals: factorDecimals},
+		{name: "trend"},
+		{name: "volatility", decimals: factorDecimals},
+		{name: "data_points"},
+	}}
+	for _, a := range analyses {
+		if a == nil {
+			continue
+		}
+		t.rows = append(t.rows, []interface{}{
+			a.Country, countryName(a.Country), a.StartYear, a.EndYear, a.Average, a.Trend, a.Volatility, a.DataPoints,
+		})
+	}
+	return t
+}
+
+// History builds a table from ppp.Client.GetHistoricalPPP results
+// Columns: country_code, country_name, year, factor, source
+func History(data []ppp.PPPData) *Table {
+	t := &Table{columns: []column{
+		{name: "country_code"},
+		{name: "country_name"},
+		{name: "year"},
+		{name: "factor", decimals: factorDecimals},
+		{name: "source"},
+	}}
+	for _, d := range data {
+		name := d.CountryName
+		if name == "" {
+			name = countryName(d.CountryCode)
+		}
+		t.rows = append(t.rows, []interface{}{d.CountryCode, name, d.Year, d.Factor, d.Source})
+	}
+	return t
+}
+
+// Batch builds a table from ppp.BatchRecommend results, sorted by country
+// Prices are in the local currency of each country and rounded to its
+// minor unit. BatchRecommend marks failed countries with 0; their price cell
+// is left empty, or null in JSON Lines
+// Columns: country, country_name, price, currency
+func Batch(prices map[string]float64) *Table {
+	t := &Table{columns: []column{
+		{name: "country"},
+		{name: "country_name"},
+		{name: "price", decimals: 2},
+		{name: "currency"},
+	}}
+	
+	countries := make([]string, 0, len(prices))
+	for country := range prices {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	
+	for _, country := range countries {
+		currency := ""
+		if m, ok := ppp.LookupCountryMetadata(country); ok {
+			currency = m.Currency
+		}
+		var price interface{}
+		if p := prices[country]; p > 0 {
+			price = p
+			if money, err := ppp.MoneyFromFloat(p, currency); currency != "" && err == nil {
+				price = money
+			}
+		}
+		t.rows = append(t.rows, []interface{}{country, countryName(country), price, currency})
+	}
+	return t
+}
+
+// countryName returns the ISO 3166-1 short name of a country, or ""
+func countryName(code string) string {
+	if c, ok := ppp.LookupCountry(code); ok {
+		return c.Name
+	}
+	return ""
+}
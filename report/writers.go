@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteCSV writes the table as CSV with a header row
+// Numbers use a dot as the decimal separator and no grouping in every locale
+func (t *Table) WriteCSV(w io.Writer, opts ...Option) error {
+	cfg := newConfig(opts)
+	
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.headers(cfg)); err != nil {
+		return err
+	}
+	for _, row := range t.rows {
+		if err := cw.Write(t.cells(row, "")); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONL writes the table as JSON Lines, one object per row with the
+// column names as keys, in column order. Headers are not localized, so keys
+// stay stable; numbers are JSON numbers with the column decimals
+// Example: {"country_code":"TR","country_name":"Türkiye","year":2023,"factor":11.5500,"source":"World Bank"}
+func (t *Table) WriteJSONL(w io.Writer, opts ...Option) error {
+	bw := bufio.NewWriter(w)
+	for _, row := range t.rows {
+		bw.WriteByte('{')
+		for i, c := range t.columns {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			key, _ := json.Marshal(c.name)
+			bw.Write(key)
+			bw.WriteByte(':')
+			
+			switch v := row[i].(type) {
+			case string:
+				value, _ := json.Marshal(v)
+				bw.Write(value)
+			default:
+				if text := c.format(v, ""); text != "" {
+					bw.WriteString(text)
+				} else {
+					bw.WriteString("null")
+				}
+			}
+		}
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
+
+// WriteMarkdown writes the table as a GitHub-flavored Markdown table with
+// numeric columns right-aligned
+func (t *Table) WriteMarkdown(w io.Writer, opts ...Option) error {
+	cfg := newConfig(opts)
+	
+	bw := bufio.NewWriter(w)
+	headers := t.headers(cfg)
+	align := make([]string, len(t.columns))
+	for i := range t.columns {
+		align[i] = "---"
+		if t.numeric(i) {
+			align[i] = "---:"
+		}
+	}
+	
+	writeMarkdownRow(bw, headers)
+	writeMarkdownRow(bw, align)
+	for _, row := range t.rows {
+		writeMarkdownRow(bw, t.cells(row, cfg.locale))
+	}
+	return bw.Flush()
+}
+
+// writeMarkdownRow writes one Markdown table row, escaping pipes
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	w.WriteString("|")
+	for _, cell := range cells {
+		w.WriteString(" ")
+		w.WriteString(strings.ReplaceAll(cell, "|", `\|`))
+		w.WriteString(" |")
+	}
+	w.WriteString("\n")
+}
+
+// WriteHTML writes the table as an HTML <table> element; numeric cells carry
+// class="number" so they can be right-aligned by a stylesheet
+func (t *Table) WriteHTML(w io.Writer, opts ...Option) error {
+	cfg := newConfig(opts)
+	
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, h := range t.headers(cfg) {
+		bw.WriteString("<th>" + html.EscapeString(h) + "</th>")
+	}
+	bw.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+	for _, row := range t.rows {
+		bw.WriteString("    <tr>")
+		for i, cell := range t.cells(row, cfg.locale) {
+			if isNumber(row[i]) {
+				bw.WriteString(`<td class="number">`)
+			} else {
+				bw.WriteString("<td>")
+			}
+			bw.WriteString(html.EscapeString(cell) + "</td>")
+		}
+		bw.WriteString("</tr>\n")
+	}
+	bw.WriteString("  </tbody>\n</table>\n")
+	return bw.Flush()
+}
+
+// headers returns the header row
+func (t *Table) headers(cfg *config) []string {
+	headers := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		headers[i] = cfg.header(c.name)
+	}
+	return headers
+}
+
+// cells returns the text of a row
+func (t *Table) cells(row []interface{}, locale string) []string {
+	cells := make([]string, len(row))
+	for i, v := range row {
+		cells[i] = t.columns[i].format(v, locale)
+	}
+	return cells
+}
+
+// numeric reports whether a column has rows and every cell is a number
+func (t *Table) numeric(i int) bool {
+	found := false
+	for _, row := range t.rows {
+		if !isNumber(row[i]) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vahaponur/ppp-go"
+)
+
+// BIN prefix lengths accepted in tables
+const (
+	minBINLength = 4
+	maxBINLength = 11
+)
+
+// binColumns and binCountryColumns are header names recognised in BIN CSVs,
+// in order of preference
+var (
+	binColumns        = []string{"bin", "iin", "prefix"}
+	binCountryColumns = []string{"alpha_2", "alpha2", "country_code", "iso_country", "country_iso", "isocode2", "country"}
+)
+
+// BINTable maps card number prefixes (BIN/IIN) to issuing countries
+// Lookups use the longest matching prefix. It is safe for concurrent use
+type BINTable struct {
+	prefixes map[string]string
+	minLen   int
+	maxLen   int
+}
+
+// NewBINTable creates a table from prefixes and country codes
+// Example: NewBINTable(map[string]string{"454360": "TR", "4147": "US"})
+func NewBINTable(entries map[string]string) (*BINTable, error) {
+	t := &BINTable{prefixes: make(map[string]string, len(entries))}
+	for prefix, country := range entries {
+		if err := t.add(prefix, country); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// OpenBINs imports a BIN CSV file
+func OpenBINs(path string) (*BINTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBINs(f)
+}
+
+// LoadBINs imports a BIN CSV. Without a header, the first two columns are
+// the prefix and country. With a header, columns such as "bin" and
+// "alpha_2" or "country_code" are used, so binlist-style exports load
+// as is. Country codes may be alpha-2, alpha-3 or numeric; rows without a
+// known country are skipped
+func LoadBINs(r io.Reader) (*BINTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	
+	t := &BINTable{prefixes: make(map[string]string)}
+	binCol, countryCol := 0, 1
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		
+		if line == 1 && !isDigits(strings.TrimSpace(row[0])) {
+			binCol, countryCol = headerColumn(row, binColumns), headerColumn(row, binCountryColumns)
+			if binCol < 0 || countryCol < 0 {
+				return nil, fmt.Errorf("%w: header has no BIN or country column", ErrInvalidData)
+			}
+			continue
+		}
+		if binCol >= len(row) || countryCol >= len(row) {
+			return nil, fmt.Errorf("%w: line %d has %d columns", ErrInvalidData, line, len(row))
+		}
+		
+		country, err := ppp.NormalizeCountryCode(row[countryCol])
+		if err != nil {
+			continue
+		}
+		if err := t.add(row[binCol], country); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return t, nil
+}
+
+// headerColumn returns the index of the first recognised column name
+func headerColumn(header, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// add adds a prefix to the table
+func (t *BINTable) add(prefix, country string) error {
+	prefix = strings.TrimSpace(prefix)
+	if len(prefix) < minBINLength || len(prefix) > maxBINLength || !isDigits(prefix) {
+		return fmt.Errorf("%w: invalid BIN %q", ErrInvalidData, prefix)
+	}
+	country, err := ppp.NormalizeCountryCode(country)
+	if err != nil {
+		return fmt.Errorf("%w: BIN %s: %v", ErrInvalidData, prefix, err)
+	}
+	
+	t.prefixes[prefix] = country
+	if t.minLen == 0 || len(prefix) < t.minLen {
+		t.minLen = len(prefix)
+	}
+	if len(prefix) > t.maxLen {
+		t.maxLen = len(prefix)
+	}
+	return nil
+}
+
+// Len returns the number of prefixes
+func (t *BINTable) Len() int {
+	return len(t.prefixes)
+}
+
+// Country returns the issuing country of a card number or its leading
+// digits. Spaces and dashes are ignored
+// Example: Country("4543 6012 3456 7890") returns "TR", true
+func (t *BINTable) Country(cardNumber string) (string, bool) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(cardNumber)
+	if !isDigits(digits) {
+		return "", false
+	}
+	
+	for n := min(t.maxLen, len(digits)); n >= t.minLen && n > 0; n-- {
+		if country, ok := t.prefixes[digits[:n]]; ok {
+			return country, true
+		}
+	}
+	return "", false
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package risk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// labeledRange is an inclusive address range with an optional label
+type labeledRange struct {
+	start netip.Addr
+	end   netip.Addr
+	label string
+}
+
+// IPRanges is a list of address ranges, such as hosting providers, VPN exit
+// nodes and public proxies. It is safe for concurrent use
+type IPRanges struct {
+	ranges []labeledRange
+}
+
+// NewIPRanges creates a list from prefixes
+func NewIPRanges(prefixes ...netip.Prefix) *IPRanges {
+	l := &IPRanges{}
+	for _, p := range prefixes {
+		start, end := prefixBounds(p)
+		l.ranges = append(l.ranges, labeledRange{start: start, end: end})
+	}
+	l.sort()
+	return l
+}
+
+// OpenIPRanges imports an IP range list file
+func OpenIPRanges(path string) (*IPRanges, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadIPRanges(f)
+}
+
+// LoadIPRanges imports one range per line, as a CIDR, a single address, or
+// start and end addresses ("1.2.3.0,1.2.3.255" or "1.2.3.0-1.2.3.255"),
+// optionally followed by a label column such as the provider name
+// Lines starting with # and a header row are ignored
+// Example line: "3.5.140.0/22,AWS"
+func LoadIPRanges(r io.Reader) (*IPRanges, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	
+	l := &IPRanges{}
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		
+		rng, err := parseRangeRow(row)
+		if err != nil {
+			if line == 1 {
+				// Header row
+				continue
+			}
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidData, line, err)
+		}
+		l.ranges = append(l.ranges, rng)
+	}
+	
+	l.sort()
+	return l, nil
+}
+
+// parseRangeRow parses a CIDR, address or address range and its label
+func parseRangeRow(row []string) (labeledRange, error) {
+	first := strings.TrimSpace(row[0])
+	rest := row[1:]
+	
+	var rng labeledRange
+	if prefix, err := netip.ParsePrefix(first); err == nil {
+		rng.start, rng.end = prefixBounds(prefix)
+	} else if start, end, ok := strings.Cut(first, "-"); ok {
+		if rng.start, err = netip.ParseAddr(strings.TrimSpace(start)); err != nil {
+			return rng, err
+		}
+		if rng.end, err = netip.ParseAddr(strings.TrimSpace(end)); err != nil {
+			return rng, err
+		}
+	} else if rng.start, err = netip.ParseAddr(first); err != nil {
+		return rng, err
+	} else if len(rest) > 0 {
+		if end, err := netip.ParseAddr(strings.TrimSpace(rest[0])); err == nil {
+			rng.end = end
+			rest = rest[1:]
+		}
+	}
+	
+	if !rng.end.IsValid() {
+		rng.end = rng.start
+	}
+	rng.start, rng.end = rng.start.Unmap(), rng.end.Unmap()
+	if rng.start.Is4() != rng.end.Is4() || rng.end.Less(rng.start) {
+		return rng, fmt.Errorf("invalid range %s-%s", rng.start, rng.end)
+	}
+	if len(rest) > 0 {
+		rng.label = strings.TrimSpace(rest[0])
+	}
+	return rng, nil
+}
+
+// prefixBounds returns the first and last addresses of a prefix
+func prefixBounds(p netip.Prefix) (netip.Addr, netip.Addr) {
+	p = p.Masked()
+	start := p.Addr().Unmap()
+	b := start.AsSlice()
+	bits := p.Bits()
+	if p.Addr().Is4In6() {
+		bits -= 96
+	}
+	for i := bits; i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	end, _ := netip.AddrFromSlice(b)
+	return start, end
+}
+
+// sort orders the ranges and merges overlapping ones, keeping the first label
+func (l *IPRanges) sort() {
+	sort.SliceStable(l.ranges, func(i, j int) bool {
+		return l.ranges[i].start.Less(l.ranges[j].start)
+	})
+	
+	merged := l.ranges[:0]
+	for _, r := range l.ranges {
+		if n := len(merged); n > 0 && !merged[n-1].end.Less(r.start) && merged[n-1].start.Is4() == r.start.Is4() {
+			if merged[n-1].end.Less(r.end) {
+				merged[n-1].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	l.ranges = merged
+}
+
+// Len returns the number of ranges after merging overlaps
+func (l *IPRanges) Len() int {
+	return len(l.ranges)
+}
+
+// Lookup reports whether ip is in the list and returns its label
+func (l *IPRanges) Lookup(ip netip.Addr) (string, bool) {
+	ip = ip.Unmap()
+	i := sort.Search(len(l.ranges), func(i int) bool {
+		return ip.Less(l.ranges[i].start)
+	}) - 1
+	if i < 0 || l.ranges[i].end.Less(ip) {
+		return "", false
+	}
+	return l.ranges[i].label, true
+}
+
+// Contains reports whether ip is in the list
+func (l *IPRanges) Contains(ip netip.Addr) bool {
+	_, ok := l.Lookup(ip)
+	return ok
+}
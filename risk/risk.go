@@ -0,0 +1,317 @@
+// Package risk scores how likely a visitor is to be misrepresenting their
+// country to get a PPP discount, e.g. through a VPN or proxy
+//
+// The scorer compares the country the price is requested for with the
+// visitor's GeoIP country, Accept-Language region, time zone and payment card
+// country, and flags addresses in a supplied datacenter range list. The
+// result is a score from 0 to 1 and the reasons behind it
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/geo"
+	"github.com/vahaponur/ppp-go/middleware"
+)
+
+// ErrInvalidData is returned for malformed BIN tables and IP range lists
+var ErrInvalidData = errors.New("invalid risk data")
+
+// Reason codes
+const (
+	ReasonDatacenterIP        = "DATACENTER_IP"
+	ReasonIPCountryMismatch   = "IP_COUNTRY_MISMATCH"
+	ReasonCardCountryMismatch = "CARD_COUNTRY_MISMATCH"
+	ReasonTimeZoneMismatch    = "TIMEZONE_MISMATCH"
+	ReasonLanguageMismatch    = "LANGUAGE_REGION_MISMATCH"
+)
+
+// DefaultWeights are the weights of each reason
+// Accept-Language is weak evidence, as many people browse in en-US
+var DefaultWeights = map[string]float64{
+	ReasonDatacenterIP:        0.6,
+	ReasonCardCountryMismatch: 0.6,
+	ReasonIPCountryMismatch:   0.4,
+	ReasonTimeZoneMismatch:    0.35,
+	ReasonLanguageMismatch:    0.15,
+}
+
+// DefaultThreshold is the score at which the discounted price is denied
+const DefaultThreshold = 0.5
+
+// Signals are the observations about a visitor
+// Leave a field empty when the signal is unavailable
+type Signals struct {
+	IP             netip.Addr // client IP address
+	IPCountry      string     // country from a CDN header or GeoIP; resolved from IP if empty
+	AcceptLanguage string     // Accept-Language header
+	TimeZone       string     // IANA zone from Intl.DateTimeFormat().resolvedOptions().timeZone
+	UTCOffset      *int       // minutes east of UTC; negate JavaScript's getTimezoneOffset()
+	CardCountry    string     // card issuing country, if the payment provider reports it
+	CardNumber     string     // card number or its leading digits, looked up in the BIN table
+}
+
+// Reason is a signal that contradicts the requested country
+type Reason struct {
+	Code    string  // e.g. ReasonIPCountryMismatch
+	Message string  // human readable explanation
+	Country string  // country the signal points to, if known
+	Weight  float64 // contribution to the score
+}
+
+// Assessment is the result of scoring a request
+type Assessment struct {
+	Country string   // requested country
+	Score   float64  // from 0 (consistent) to 1 (contradicted by every signal)
+	Deny    bool     // Score reached the threshold; don't offer the PPP price
+	Reasons []Reason // in decreasing weight
+}
+
+// Option configures a Scorer
+type Option func(*Scorer)
+
+// Scorer scores requests for PPP prices
+// It is safe for concurrent use
+type Scorer struct {
+	resolver    geo.Resolver
+	bins        *BINTable
+	datacenters *IPRanges
+	weights     map[string]float64
+	threshold   float64
+	clock       ppp.Clock
+}
+
+// WithResolver resolves Signals.IP when Signals.IPCountry is empty
+func WithResolver(resolver geo.Resolver) Option {
+	return func(s *Scorer) {
+		s.resolver = resolver
+	}
+}
+
+// WithBINTable sets the table for Signals.CardNumber lookups
+func WithBINTable(bins *BINTable) Option {
+	return func(s *Scorer) {
+		s.bins = bins
+	}
+}
+
+// WithDatacenterRanges sets the hosting, VPN and proxy address ranges
+func WithDatacenterRanges(ranges *IPRanges) Option {
+	return func(s *Scorer) {
+		s.datacenters = ranges
+	}
+}
+
+// WithWeight sets the weight of a reason, from 0 to 1
+// A weight of 0 disables the check
+func WithWeight(code string, weight float64) Option {
+	return func(s *Scorer) {
+		s.weights[code] = weight
+	}
+}
+
+// WithThreshold sets the score at which Assessment.Deny is set
+func WithThreshold(threshold float64) Option {
+	return func(s *Scorer) {
+		s.threshold = threshold
+	}
+}
+
+// WithClock sets the clock used to date UTC offset checks of legacy time
+// zone names (default: ppp.SystemClock)
+func WithClock(clock ppp.Clock) Option {
+	return func(s *Scorer) {
+		s.clock = clock
+	}
+}
+
+// NewScorer creates a scorer
+// Example: NewScorer(WithResolver(db), WithDatacenterRanges(ranges))
+func NewScorer(opts ...Option) *Scorer {
+	s := &Scorer{
+		weights:   make(map[string]float64, len(DefaultWeights)),
+		threshold: DefaultThreshold,
+		clock:     ppp.SystemClock,
+	}
+	for code, weight := range DefaultWeights {
+		s.weights[code] = weight
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Score assesses whether the signals support pricing for country
+// Weights combine as independent evidence: 1 - (1-w1)(1-w2)...
+// Mismatches pointing to a country with the same or lower income level give
+// no reason to fake a location and count half
+func (s *Scorer) Score(ctx context.Context, country string, signals Signals) (*Assessment, error) {
+	country, err := ppp.NormalizeCountryCode(country)
+	if err != nil {
+		return nil, err
+	}
+	
+	a := &Assessment{Country: country}
+	add := func(code, message, other string) {
+		weight := s.weights[code]
+		if other != "" && !moreAffluent(other, country) {
+			weight /= 2
+		}
+		if weight <= 0 {
+			return
+		}
+		a.Reasons = append(a.Reasons, Reason{Code: code, Message: message, Country: other, Weight: weight})
+	}
+	
+	ip := signals.IP.Unmap()
+	if s.datacenters != nil && ip.IsValid() {
+		if label, ok := s.datacenters.Lookup(ip); ok {
+			message := fmt.Sprintf("%s is a datacenter address", ip)
+			if label != "" {
+				message = fmt.Sprintf("%s belongs to %s", ip, label)
+			}
+			add(ReasonDatacenterIP, message, "")
+		}
+	}
+	
+	ipCountry, err := s.ipCountry(ctx, signals)
+	if err != nil {
+		return nil, err
+	}
+	if ipCountry != "" && ipCountry != country {
+		add(ReasonIPCountryMismatch, fmt.Sprintf("IP address is in %s", ipCountry), ipCountry)
+	}
+	
+	if cardCountry := s.cardCountry(signals); cardCountry != "" && cardCountry != country {
+		add(ReasonCardCountryMismatch, fmt.Sprintf("card was issued in %s", cardCountry), cardCountry)
+	}
+	
+	if zoneCountry, mismatch := timeZoneMismatch(country, signals, s.clock.Now()); mismatch {
+		message := "time zone is not used in " + country
+		if zoneCountry != "" {
+			message = fmt.Sprintf("time zone %s is in %s", signals.TimeZone, zoneCountry)
+		}
+		add(ReasonTimeZoneMismatch, message, zoneCountry)
+	}
+	
+	if region, ok := ppp.CountryFromAcceptLanguage(signals.AcceptLanguage); ok && region != country {
+		add(ReasonLanguageMismatch, fmt.Sprintf("preferred language is for %s", region), region)
+	}
+	
+	consistent := 1.0
+	for _, r := range a.Reasons {
+		consistent *= 1 - r.Weight
+	}
+	sort.SliceStable(a.Reasons, func(i, j int) bool {
+		return a.Reasons[i].Weight > a.Reasons[j].Weight
+	})
+	a.Score = 1 - consistent
+	a.Deny = a.Score >= s.threshold
+	
+	return a, nil
+}
+
+// ipCountry returns the IP country signal, resolving the address if needed
+func (s *Scorer) ipCountry(ctx context.Context, signals Signals) (string, error) {
+	if signals.IPCountry != "" {
+		country, err := ppp.NormalizeCountryCode(signals.IPCountry)
+		if err != nil {
+			return "", nil
+		}
+		return country, nil
+	}
+	if s.resolver == nil || !signals.IP.IsValid() {
+		return "", nil
+	}
+	
+	country, err := s.resolver.Country(ctx, signals.IP.Unmap())
+	if errors.Is(err, geo.ErrNotFound) {
+		return "", nil
+	}
+	return country, err
+}
+
+// cardCountry returns the card country signal, from the BIN table if needed
+func (s *Scorer) cardCountry(signals Signals) string {
+	if signals.CardCountry != "" {
+		country, err := ppp.NormalizeCountryCode(signals.CardCountry)
+		if err != nil {
+			return ""
+		}
+		return country
+	}
+	if s.bins == nil || signals.CardNumber == "" {
+		return ""
+	}
+	country, _ := s.bins.Country(signals.CardNumber)
+	return country
+}
+
+// timeZoneMismatch checks the time zone or UTC offset against the country
+// It returns the zone's country when the zone identifies one; offsets of
+// legacy zone names are taken in January and July of now's year
+func timeZoneMismatch(country string, signals Signals, now time.Time) (string, bool) {
+	if signals.TimeZone != "" {
+		if zoneCountry, ok := ppp.TimeZoneCountry(signals.TimeZone); ok {
+			return zoneCountry, zoneCountry != country
+		}
+		
+		// Legacy names such as "Asia/Calcutta": compare their offsets
+		if loc, err := time.LoadLocation(signals.TimeZone); err == nil {
+			year := now.Year()
+			for _, month := range []time.Month{time.January, time.July} {
+				_, offset := time.Date(year, month, 1, 12, 0, 0, 0, loc).Zone()
+				if ppp.CountryHasUTCOffset(country, offset/60) {
+					return "", false
+				}
+			}
+			return "", true
+		}
+	}
+	
+	if signals.UTCOffset != nil && len(ppp.CountryUTCOffsets(country)) > 0 {
+		return "", !ppp.CountryHasUTCOffset(country, *signals.UTCOffset)
+	}
+	
+	return "", false
+}
+
+// incomeRanks orders World Bank income levels
+var incomeRanks = map[string]int{"LIC": 1, "LMC": 2, "UMC": 3, "HIC": 4}
+
+// moreAffluent reports whether other has a higher income level than country
+// Countries without an income level count as more affluent
+func moreAffluent(other, country string) bool {
+	o, ok := ppp.LookupCountryMetadata(other)
+	if !ok || incomeRanks[o.IncomeLevel.ID] == 0 {
+		return true
+	}
+	c, ok := ppp.LookupCountryMetadata(country)
+	if !ok {
+		return true
+	}
+	return incomeRanks[o.IncomeLevel.ID] > incomeRanks[c.IncomeLevel.ID]
+}
+
+// FromRequest collects the signals the pricing middleware found for a
+// request: the client IP, the CDN or GeoIP country and Accept-Language
+// Add the time zone and card details from your checkout form
+func FromRequest(r *http.Request) Signals {
+	signals := Signals{AcceptLanguage: r.Header.Get("Accept-Language")}
+	if p, ok := middleware.FromContext(r.Context()); ok {
+		signals.IP = p.IP
+		signals.IPCountry = p.Signals[middleware.SourceHeader]
+		if signals.IPCountry == "" {
+			signals.IPCountry = p.Signals[middleware.SourceGeoIP]
+		}
+	}
+	return signals
+}
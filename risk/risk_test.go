@@ -0,0 +1,263 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vahaponur/ppp-go"
+	"github.com/vahaponur/ppp-go/geo"
+	"github.com/vahaponur/ppp-go/middleware"
+)
+
+func testScorer(t *testing.T) *Scorer {
+	t.Helper()
+	resolver, err := geo.LoadCSV(strings.NewReader("88.255.0.0,88.255.255.255,TR\n8.8.8.0,8.8.8.255,US\n3.5.140.0,3.5.143.255,DE\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bins, err := NewBINTable(map[string]string{"454360": "TR", "4147": "US", "414720": "GBR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datacenters, err := LoadIPRanges(strings.NewReader("cidr,provider\n3.5.140.0/22,AWS\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewScorer(WithResolver(resolver), WithBINTable(bins), WithDatacenterRanges(datacenters))
+}
+
+func codes(a *Assessment) []string {
+	var out []string
+	for _, r := range a.Reasons {
+		out = append(out, r.Code)
+	}
+	return out
+}
+
+func TestScore(t *testing.T) {
+	scorer := testScorer(t)
+	ctx := context.Background()
+	offset := func(minutes int) *int { return &minutes }
+
+	tests := []struct {
+		name    string
+		country string
+		signals Signals
+		reasons []string
+		deny    bool
+	}{
+		{
+			name:    "consistent",
+			country: "TR",
+			signals: Signals{
+				IP:             netip.MustParseAddr("88.255.1.1"),
+				AcceptLanguage: "tr-TR,en;q=0.8",
+				TimeZone:       "Europe/Istanbul",
+				CardNumber:     "4543 6012 3456 7890",
+			},
+		},
+		{
+			name:    "vpn from the US",
+			country: "TR",
+			signals: Signals{
+				IP:             netip.MustParseAddr("3.5.141.10"),
+				AcceptLanguage: "en-US",
+				TimeZone:       "America/New_York",
+				CardNumber:     "4147 1234",
+			},
+			reasons: []string{ReasonDatacenterIP, ReasonCardCountryMismatch, ReasonIPCountryMismatch, ReasonTimeZoneMismatch, ReasonLanguageMismatch},
+			deny:    true,
+		},
+		{
+			name:    "language alone",
+			country: "TR",
+			signals: Signals{IP: netip.MustParseAddr("88.255.1.1"), AcceptLanguage: "en-US"},
+			reasons: []string{ReasonLanguageMismatch},
+		},
+		{
+			name:    "utc offset",
+			country: "IN",
+			signals: Signals{UTCOffset: offset(-300), CardCountry: "USA"},
+			reasons: []string{ReasonCardCountryMismatch, ReasonTimeZoneMismatch},
+			deny:    true,
+		},
+		{
+			name:    "matching utc offset",
+			country: "IN",
+			signals: Signals{UTCOffset: offset(330)},
+		},
+		{
+			name:    "legacy zone name",
+			country: "IN",
+			signals: Signals{TimeZone: "Asia/Calcutta"},
+		},
+		{
+			name:    "longest BIN prefix",
+			country: "US",
+			signals: Signals{CardNumber: "4147201234567890"},
+			reasons: []string{ReasonCardCountryMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := scorer.Score(ctx, tt.country, tt.signals)
+			if err != nil {
+				t.Fatalf("Score failed: %v", err)
+			}
+			if got := strings.Join(codes(a), ","); got != strings.Join(tt.reasons, ",") {
+				t.Errorf("Reasons = %s, want %s", got, strings.Join(tt.reasons, ","))
+			}
+			if a.Deny != tt.deny {
+				t.Errorf("Deny = %v with score %.2f, want %v", a.Deny, a.Score, tt.deny)
+			}
+		})
+	}
+
+	if _, err := scorer.Score(ctx, "ZZ", Signals{}); !errors.Is(err, ppp.ErrInvalidCountry) {
+		t.Errorf("Expected ErrInvalidCountry, got %v", err)
+	}
+}
+
+func TestScoreClock(t *testing.T) {
+	ctx := context.Background()
+	signals := Signals{TimeZone: "Singapore"}
+
+	// Singapore moved from UTC+7:30 to UTC+8 in 1982
+	for year, reasons := range map[int]string{1980: ReasonTimeZoneMismatch, 2024: ""} {
+		scorer := NewScorer(WithClock(ppp.FixedClock(time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC))))
+		a, err := scorer.Score(ctx, "SG", signals)
+		if err != nil {
+			t.Fatalf("Score failed: %v", err)
+		}
+		if got := strings.Join(codes(a), ","); got != reasons {
+			t.Errorf("%d: Reasons = %q, want %q", year, got, reasons)
+		}
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	ctx := context.Background()
+
+	// Independent evidence: 1 - (1-0.4)(1-0.6)
+	a, _ := NewScorer().Score(ctx, "TR", Signals{IPCountry: "US", CardCountry: "US"})
+	if math.Abs(a.Score-0.76) > 1e-9 {
+		t.Errorf("Score = %v, want 0.76", a.Score)
+	}
+
+	// No incentive to claim a richer country
+	a, _ = NewScorer().Score(ctx, "US", Signals{IPCountry: "TR"})
+	if len(a.Reasons) != 1 || a.Reasons[0].Weight != 0.2 {
+		t.Errorf("Expected a halved weight, got %+v", a.Reasons)
+	}
+
+	a, _ = NewScorer(WithWeight(ReasonLanguageMismatch, 0), WithThreshold(0.3)).Score(ctx, "TR", Signals{IPCountry: "DE", AcceptLanguage: "de-DE"})
+	if len(a.Reasons) != 1 || !a.Deny {
+		t.Errorf("Expected only the IP reason and a denial, got %+v", a)
+	}
+}
+
+func TestBINTable(t *testing.T) {
+	const binlist = `bin,brand,type,alpha_2,alpha_3,country
+454360,VISA,debit,TR,TUR,Turkey
+414720,VISA,credit,US,USA,United States
+535522,MASTERCARD,credit,,,
+`
+	bins, err := LoadBINs(strings.NewReader(binlist))
+	if err != nil {
+		t.Fatalf("LoadBINs failed: %v", err)
+	}
+	if bins.Len() != 2 {
+		t.Errorf("Expected 2 prefixes, got %d", bins.Len())
+	}
+	if country, ok := bins.Country("4543-6012-3456-7890"); !ok || country != "TR" {
+		t.Errorf("Country = %q, %v; want TR", country, ok)
+	}
+	if _, ok := bins.Country("5355 2212"); ok {
+		t.Error("Expected no country for a row without one")
+	}
+
+	plain, err := LoadBINs(strings.NewReader("4147,USA\n"))
+	if err != nil || plain.Len() != 1 {
+		t.Fatalf("LoadBINs without a header: %v", err)
+	}
+	if country, _ := plain.Country("4147 0000"); country != "US" {
+		t.Errorf("Expected alpha-3 countries to be normalized, got %q", country)
+	}
+
+	if _, err := LoadBINs(strings.NewReader("41x7,US\n")); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData, got %v", err)
+	}
+	if _, err := NewBINTable(map[string]string{"4147": "ZZ"}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData for an unknown country, got %v", err)
+	}
+}
+
+func TestIPRanges(t *testing.T) {
+	const list = `# hosting providers
+3.5.140.0/22,AWS
+3.5.141.0/24,AWS duplicate
+34.0.0.0-34.0.0.255,GCP
+45.0.0.1
+104.16.0.0, 104.16.0.255, Cloudflare
+2a05:d000::/25,AWS
+`
+	ranges, err := LoadIPRanges(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("LoadIPRanges failed: %v", err)
+	}
+	if ranges.Len() != 5 {
+		t.Errorf("Expected 5 merged ranges, got %d", ranges.Len())
+	}
+
+	tests := map[string]string{
+		"3.5.143.255":       "AWS",
+		"::ffff:3.5.140.1":  "AWS",
+		"34.0.0.128":        "GCP",
+		"45.0.0.1":          "",
+		"104.16.0.9":        "Cloudflare",
+		"2a05:d07f:ffff::1": "AWS",
+	}
+	for ip, want := range tests {
+		label, ok := ranges.Lookup(netip.MustParseAddr(ip))
+		if !ok || label != want {
+			t.Errorf("Lookup(%s) = %q, %v; want %q", ip, label, ok, want)
+		}
+	}
+	for _, ip := range []string{"3.5.144.0", "45.0.0.2", "2a05:d080::1", "10.0.0.1"} {
+		if ranges.Contains(netip.MustParseAddr(ip)) {
+			t.Errorf("Expected %s not to be listed", ip)
+		}
+	}
+
+	if !NewIPRanges(netip.MustParsePrefix("10.0.0.0/8")).Contains(netip.MustParseAddr("10.255.0.1")) {
+		t.Error("Expected NewIPRanges to include the whole prefix")
+	}
+	if _, err := LoadIPRanges(strings.NewReader("1.0.0.0/8\n2.0.0.0-1.0.0.0\n")); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData for a reversed range, got %v", err)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	var signals Signals
+	handler := middleware.New(middleware.WithClient(ppp.NewClient()), middleware.WithCountryHeaders("CF-IPCountry"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signals = FromRequest(r)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.7:443"
+	r.Header.Set("CF-IPCountry", "DE")
+	r.Header.Set("Accept-Language", "de-DE")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if signals.IP.String() != "203.0.113.7" || signals.IPCountry != "DE" || signals.AcceptLanguage != "de-DE" {
+		t.Errorf("Unexpected signals %+v", signals)
+	}
+}
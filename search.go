@@ -0,0 +1,277 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// countryNamesData holds alternative country names: short names,
+// abbreviations, World Bank spellings and native or localized names
+//go:embed data/countrynames.json
+var countryNamesData []byte
+
+// CountryMatch is a scored country search result
+type CountryMatch struct {
+	Code    string  `json:"code"`    // ISO 3166-1 alpha-2 code
+	Name    string  `json:"name"`    // English ISO name
+	Matched string  `json:"matched"` // the name or code that matched the query
+	Score   float64 `json:"score"`   // 1 for an exact match, lower for weaker matches
+}
+
+// minSearchScore is the lowest score SearchCountries returns
+const minSearchScore = 0.5
+
+// searchName is a folded candidate name of a country
+type searchName struct {
+	code   string
+	name   string
+	folded string
+	tokens []string
+}
+
+var (
+	searchNamesOnce sync.Once
+	searchNames     []searchName
+)
+
+// loadSearchNames builds the candidate list from the ISO registry and the
+// alternative names
+func loadSearchNames() []searchName {
+	searchNamesOnce.Do(func() {
+		var alternatives map[string][]string
+		if err := json.Unmarshal(countryNamesData, &alternatives); err != nil {
+			panic("ppp: invalid embedded country names: " + err.Error())
+		}
+		
+		add := func(code, name string) {
+			folded := foldName(name)
+			if folded == "" {
+				return
+			}
+			searchNames = append(searchNames, searchName{
+				code:   code,
+				name:   name,
+				folded: folded,
+				tokens: nameTokens(folded),
+			})
+		}
+		
+		for _, c := range loadCountries().countries {
+			add(c.Alpha2, c.Name)
+			for _, name := range alternatives[c.Alpha2] {
+				add(c.Alpha2, name)
+			}
+		}
+	})
+	return searchNames
+}
+
+// SearchCountries finds countries by name, returning ranked candidates
+// Matches English, native and localized names, common short names and
+// abbreviations ("USA", "UAE"), ISO codes, and tolerates typos ("Germny").
+// limit caps the number of results; 0 returns all matches
+// Example: SearchCountries("Deutschland", 1) returns DE with score 1
+func SearchCountries(query string, limit int) []CountryMatch {
+	q := foldName(query)
+	if q == "" {
+		return nil
+	}
+	qTokens := nameTokens(q)
+	
+	best := make(map[string]CountryMatch)
+	consider := func(code, matched string, score float64) {
+		if score < minSearchScore {
+			return
+		}
+		if current, ok := best[code]; ok && current.Score >= score {
+			return
+		}
+		c, _ := LookupCountry(code)
+		best[code] = CountryMatch{Code: code, Name: c.Name, Matched: matched, Score: score}
+	}
+	
+	// Codes match exactly, case-insensitively
+	if len(q) == 2 || len(q) == 3 {
+		if c, ok := LookupCountry(q); ok && isAlpha(q) {
+			consider(c.Alpha2, strings.ToUpper(q), 0.97)
+		}
+	}
+	
+	for _, n := range loadSearchNames() {
+		consider(n.code, n.name, scoreName(q, qTokens, n))
+	}
+	
+	matches := make([]CountryMatch, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// scoreName rates how well a folded query matches a candidate name
+func scoreName(q string, qTokens []string, n searchName) float64 {
+	if q == n.folded {
+		return 1
+	}
+	
+	qLen, nLen := float64(len([]rune(q))), float64(len([]rune(n.folded)))
+	score := 0.0
+	
+	if qLen >= 3 && strings.HasPrefix(n.folded, q) {
+		score = 0.9 + 0.05*qLen/nLen
+	}
+	
+	// Every query word matches a word of the name: "korea south" or "united arab"
+	if len(qTokens) > 0 {
+		matched := 0
+		for _, qt := range qTokens {
+			for _, nt := range n.tokens {
+				if qt == nt || (len(qt) >= 3 && strings.HasPrefix(nt, qt)) {
+					matched++
+					break
+				}
+			}
+		}
+		if matched == len(qTokens) && len(n.tokens) > 0 {
+			score = maxScore(score, 0.75+0.15*float64(matched)/float64(len(n.tokens)))
+		}
+	}
+	
+	if qLen >= 3 && strings.Contains(n.folded, q) {
+		score = maxScore(score, 0.6+0.2*qLen/nLen)
+	}
+	
+	// Typos: edit distance against the whole name
+	if qLen >= 4 {
+		distance := float64(editDistance(q, n.folded))
+		similarity := 1 - distance/maxScore(qLen, nLen)
+		if similarity >= 0.75 {
+			score = maxScore(score, 0.85*similarity)
+		}
+	}
+	
+	return score
+}
+
+// maxScore returns the larger of two scores
+func maxScore(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// diacriticFolds maps accented Latin letters to their base letters
+var diacriticFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ệ': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i", 'ī': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe", 'ř': "r", 'ś': "s", 'ş': "s", 'š': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z", 'þ': "th",
+}
+
+// foldName lowercases a name, removes diacritics and punctuation and
+// collapses spaces, so "Côte d'Ivoire" becomes "cote d ivoire"
+func foldName(s string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if fold, ok := diacriticFolds[r]; ok {
+			b.WriteString(fold)
+			space = false
+			continue
+		}
+		
+		switch {
+		case r == '.':
+			// Abbreviations: "U.S.A." is "usa"
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks, e.g. vowel signs in Indic scripts, belong to the letter
+			b.WriteRune(r)
+		case !space:
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// nameTokens splits a folded name into words, dropping filler words
+func nameTokens(folded string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(folded) {
+		switch word {
+		case "the", "of", "and":
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// editDistance returns the optimal string alignment distance between a and b,
+// counting insertions, deletions, substitutions and adjacent transpositions
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	
+	// Three rolling rows: two back, previous and current
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				if transposed := prev2[j-2] + 1; transposed < curr[j] {
+					curr[j] = transposed
+				}
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	
+	return prev[len(rb)]
+}
+
+// min3 returns the smallest of three ints
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,99 @@
+package ppp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSearchCountries(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"Türkiye", "TR"},
+		{"turkey", "TR"},
+		{"Deutschland", "DE"},
+		{"USA", "US"},
+		{"U.S.", "US"},
+		{"South Korea", "KR"},
+		{"Germny", "DE"},
+		{"Phillipines", "PH"},
+		{"UAE", "AE"},
+		{"Cote d'Ivoire", "CI"},
+		{"Ivory", "CI"},
+		{"日本", "JP"},
+		{"Россия", "RU"},
+		{"Holland", "NL"},
+		{"Korea, Rep.", "KR"},
+		{"gbr", "GB"},
+	}
+
+	for _, tt := range tests {
+		matches := SearchCountries(tt.query, 0)
+		if len(matches) == 0 || matches[0].Code != tt.want {
+			t.Errorf("SearchCountries(%q) = %+v, want %s first", tt.query, matches, tt.want)
+		}
+	}
+
+	matches := SearchCountries("korea", 0)
+	if len(matches) < 2 || matches[0].Score < matches[1].Score {
+		t.Fatalf("Expected ranked matches for korea, got %+v", matches)
+	}
+	if matches[0].Code != "KR" || matches[1].Code != "KP" {
+		t.Errorf("Expected KR then KP, got %+v", matches)
+	}
+
+	if got := SearchCountries("niger", 0); got[0].Code != "NE" || got[0].Score != 1 || got[1].Code != "NG" {
+		t.Errorf("Expected exact Niger before Nigeria, got %+v", got)
+	}
+	if got := SearchCountries("united", 2); len(got) != 2 {
+		t.Errorf("Expected limit 2, got %d", len(got))
+	}
+	if got := SearchCountries("xyzzy", 0); len(got) != 0 {
+		t.Errorf("Expected no matches, got %+v", got)
+	}
+	if got := SearchCountries("  ", 0); got != nil {
+		t.Errorf("Expected nil for blank query, got %+v", got)
+	}
+}
+
+func TestGetCountryCodeOffline(t *testing.T) {
+	// Confident matches are resolved without the World Bank country list
+	for query, want := range map[string]string{
+		"Deutschland":  "DE",
+		"South Korea":  "KR",
+		"Argentna":     "AR",
+		"Saudi Arabia": "SA",
+	} {
+		got, err := GetCountryCode(query)
+		if err != nil || got != want {
+			t.Errorf("GetCountryCode(%q) = %q, %v; want %s", query, got, err, want)
+		}
+	}
+
+	_, err := GetCountryCode("")
+	var pppErr *PPPError
+	if !errors.As(err, &pppErr) || pppErr.Code != ErrCodeInvalidInput {
+		t.Errorf("Expected INVALID_INPUT for empty name, got %v", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"germany", "germany", 0},
+		{"germny", "germany", 1},
+		{"gremany", "germany", 1},
+		{"türkiye", "turkiye", 1},
+		{"abc", "", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
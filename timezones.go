@@ -0,0 +1,67 @@
+package ppp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// timeZoneData maps countries to UTC offsets and IANA zones to countries
+// Derived from the IANA tz database zone.tab
+//go:embed data/timezones.json
+var timeZoneData []byte
+
+var (
+	timeZonesOnce  sync.Once
+	countryOffsets map[string][]int
+	zoneCountries  map[string]string
+)
+
+// loadTimeZones parses the embedded time zone data once
+func loadTimeZones() {
+	timeZonesOnce.Do(func() {
+		var data struct {
+			Offsets map[string][]int  `json:"offsets"`
+			Zones   map[string]string `json:"zones"`
+		}
+		if err := json.Unmarshal(timeZoneData, &data); err != nil {
+			panic("ppp: invalid embedded time zone data: " + err.Error())
+		}
+		countryOffsets = data.Offsets
+		zoneCountries = data.Zones
+	})
+}
+
+// CountryUTCOffsets returns the UTC offsets in use in a country, in minutes
+// east of UTC, including daylight saving time
+// Example: CountryUTCOffsets("IN") returns [330]
+func CountryUTCOffsets(code string) []int {
+	loadTimeZones()
+	normalized, err := NormalizeCountryCode(code)
+	if err != nil {
+		return nil
+	}
+	return append([]int(nil), countryOffsets[normalized]...)
+}
+
+// CountryHasUTCOffset reports whether a country uses an offset, in minutes
+// east of UTC. Negate JavaScript's Date.getTimezoneOffset() to get one
+func CountryHasUTCOffset(code string, offset int) bool {
+	for _, o := range CountryUTCOffsets(code) {
+		if o == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeZoneCountry returns the country of a canonical IANA time zone, as
+// reported by Intl.DateTimeFormat().resolvedOptions().timeZone
+// Zones shared by several countries, such as "UTC", have no country
+// Example: TimeZoneCountry("Europe/Istanbul") returns "TR", true
+func TimeZoneCountry(zone string) (string, bool) {
+	loadTimeZones()
+	country, ok := zoneCountries[strings.TrimSpace(zone)]
+	return country, ok
+}
@@ -0,0 +1,281 @@
+package ppp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// All can be passed to WithWarmCountries or WithWarmCurrencies to warm everything
+// For countries this is every country in the World Bank list; for base currencies
+// it is the currency of every warmed country
+const All = "all"
+
+// RefreshError describes a failed preload or refresh
+type RefreshError struct {
+	Family string // cache family: ppp, rate or countries
+	Key    string // country or base currency, empty for the country list
+	Err    error
+}
+
+// Error implements the error interface
+func (e *RefreshError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("refresh %s: %v", e.Family, e.Err)
+	}
+	return fmt.Sprintf("refresh %s %s: %v", e.Family, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RefreshError) Unwrap() error {
+	return e.Err
+}
+
+// Warmer preloads the client cache and keeps it warm by refreshing
+// entries before they expire
+type Warmer struct {
+	client         *Client
+	countries      []string
+	baseCurrencies []string
+	lead           float64
+	jitter         float64
+	onError        func(*RefreshError)
+}
+
+// WarmerOption is a functional option for configuring a Warmer
+type WarmerOption func(*Warmer)
+
+// WithWarmCountries sets the countries whose PPP data is warmed (or All)
+func WithWarmCountries(codes ...string) WarmerOption {
+	return func(w *Warmer) {
+		w.countries = codes
+	}
+}
+
+// WithWarmCurrencies sets the base currencies whose rate tables are warmed (or All)
+func WithWarmCurrencies(codes ...string) WarmerOption {
+	return func(w *Warmer) {
+		w.baseCurrencies = codes
+	}
+}
+
+// WithRefreshLead sets how early entries are refreshed, as a fraction of their TTL
+// Example: 0.2 refreshes an entry with a 1 hour TTL after 48 minutes
+func WithRefreshLead(fraction float64) WarmerOption {
+	return func(w *Warmer) {
+		w.lead = fraction
+	}
+}
+
+// WithRefreshJitter randomizes refresh times by up to fraction of the TTL
+// so that many instances don't hit the upstream APIs at the same moment
+func WithRefreshJitter(fraction float64) WarmerOption {
+	return func(w *Warmer) {
+		w.jitter = fraction
+	}
+}
+
+// WithRefreshErrorHandler sets a callback invoked for every failed refresh
+func WithRefreshErrorHandler(handler func(*RefreshError)) WarmerOption {
+	return func(w *Warmer) {
+		w.onError = handler
+	}
+}
+
+// NewWarmer creates a cache warmer for a client
+func NewWarmer(client *Client, opts ...WarmerOption) *Warmer {
+	w := &Warmer{
+		client:         client,
+		baseCurrencies: []string{"USD"},
+		lead:           0.2,
+		jitter:         0.05,
+	}
+	
+	for _, opt := range opts {
+		opt(w)
+	}
+	
+	return w
+}
+
+// Warm preloads the country list, PPP data and rate tables once
+// Returns all failures joined together
+func (w *Warmer) Warm(ctx context.Context) error {
+	if !w.client.cacheEnabled || w.client.cache == nil {
+		return ErrCacheDisabled
+	}
+	
+	var errs []error
+	for _, family := range []string{CacheFamilyCountries, CacheFamilyPPP, CacheFamilyRate} {
+		for _, err := range w.refresh(ctx, family) {
+			errs = append(errs, err)
+		}
+	}
+	
+	return errors.Join(errs...)
+}
+
+// Run warms the cache and then refreshes it on schedule until ctx is done
+// Failures are reported to the refresh error handler; Run returns ctx.Err()
+func (w *Warmer) Run(ctx context.Context) error {
+	if !w.client.cacheEnabled || w.client.cache == nil {
+		return ErrCacheDisabled
+	}
+	
+	families := []string{CacheFamilyCountries, CacheFamilyPPP, CacheFamilyRate}
+	next := make(map[string]time.Time, len(families))
+	for _, family := range families {
+		next[family] = w.runFamily(ctx, family)
+	}
+	
+	for {
+		// Wait for the earliest scheduled refresh
+		due := families[0]
+		for _, family := range families[1:] {
+			if next[family].Before(next[due]) {
+				due = family
+			}
+		}
+		
+		timer := time.NewTimer(time.Until(next[due]))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		
+		next[due] = w.runFamily(ctx, due)
+	}
+}
+
+// runFamily refreshes a family, reports failures and returns when it is next due
+func (w *Warmer) runFamily(ctx context.Context, family string) time.Time {
+	errs := w.refresh(ctx, family)
+	for _, err := range errs {
+		if w.onError != nil && ctx.Err() == nil {
+			w.onError(err)
+		}
+	}
+	
+	interval := w.interval(family)
+	if len(errs) > 0 {
+		// Retry sooner so entries don't expire while the upstream recovers
+		interval /= 4
+	}
+	
+	return time.Now().Add(interval)
+}
+
+// interval returns the time between refreshes of a family
+func (w *Warmer) interval(family string) time.Duration {
+	ttl := w.client.ttl(family)
+	interval := time.Duration(float64(ttl) * (1 - w.lead))
+	
+	if w.jitter > 0 {
+		spread := float64(ttl) * w.jitter
+		interval += time.Duration((rand.Float64()*2 - 1) * spread)
+	}
+	
+	if interval < time.Second {
+		interval = time.Second
+	}
+	
+	return interval
+}
+
+// refresh fetches fresh data for a family, bypassing the cache
+func (w *Warmer) refresh(ctx context.Context, family string) []*RefreshError {
+	var errs []*RefreshError
+	
+	switch family {
+	case CacheFamilyCountries:
+		if _, err := w.client.fetchCountries(ctx); err != nil {
+			errs = append(errs, &RefreshError{Family: family, Err: err})
+		}
+	case CacheFamilyPPP:
+		countries, err := w.countryCodes(ctx)
+		if err != nil {
+			return append(errs, &RefreshError{Family: CacheFamilyCountries, Err: err})
+		}
+		for _, code := range countries {
+			if ctx.Err() != nil {
+				break
+			}
+			if _, err := w.client.fetchPPP(ctx, code); err != nil {
+				errs = append(errs, &RefreshError{Family: family, Key: code, Err: err})
+			}
+		}
+	case CacheFamilyRate:
+		currencies, err := w.currencyCodes(ctx)
+		if err != nil {
+			return append(errs, &RefreshError{Family: CacheFamilyCountries, Err: err})
+		}
+		for _, code := range currencies {
+			if ctx.Err() != nil {
+				break
+			}
+			if _, err := w.client.GetRateTable(ctx, code); err != nil {
+				errs = append(errs, &RefreshError{Family: family, Key: code, Err: err})
+			}
+		}
+	}
+	
+	return errs
+}
+
+// countryCodes resolves the configured countries
+func (w *Warmer) countryCodes(ctx context.Context) ([]string, error) {
+	if !containsAll(w.countries) {
+		return w.countries, nil
+	}
+	
+	countries, err := w.client.GetCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	codes := make([]string, 0, len(countries))
+	for _, country := range countries {
+		codes = append(codes, country.ISO2Code)
+	}
+	
+	return codes, nil
+}
+
+// currencyCodes resolves the configured base currencies
+func (w *Warmer) currencyCodes(ctx context.Context) ([]string, error) {
+	if !containsAll(w.baseCurrencies) {
+		return w.baseCurrencies, nil
+	}
+	
+	countries, err := w.countryCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	seen := make(map[string]bool)
+	var codes []string
+	for _, country := range countries {
+		currency := w.client.getCurrencyForCountry(country)
+		if !seen[currency] {
+			seen[currency] = true
+			codes = append(codes, currency)
+		}
+	}
+	
+	return codes, nil
+}
+
+// containsAll reports whether codes contains the All keyword
+func containsAll(codes []string) bool {
+	for _, code := range codes {
+		if strings.EqualFold(code, All) {
+			return true
+		}
+	}
+	return false
+}
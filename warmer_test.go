@@ -0,0 +1,87 @@
+package ppp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newWarmerTestServer(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch {
+		case r.URL.Path == "/country":
+			fmt.Fprint(w, `[{"page":1},[{"id":"TUR","iso2Code":"TR","name":"Turkiye","capitalCity":"Ankara"}]]`)
+		case strings.HasPrefix(r.URL.Path, "/country/"):
+			code := strings.Split(r.URL.Path, "/")[2]
+			fmt.Fprintf(w, `[{"page":1},[{"country":{"id":%q,"value":"Test"},"date":"2023","value":11.55}]]`, code)
+		case r.URL.Path == "/currencies/usd.json":
+			fmt.Fprint(w, `{"date":"2024-05-01","usd":{"try":32.5,"eur":0.93}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWarmerWarm(t *testing.T) {
+	var requests int32
+	server := newWarmerTestServer(t, &requests)
+	client := NewClient(WithWorldBankURL(server.URL), WithCurrencyURL(server.URL))
+
+	warmer := NewWarmer(client, WithWarmCountries(All), WithWarmCurrencies("USD"))
+	if err := warmer.Warm(context.Background()); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+
+	before := atomic.LoadInt32(&requests)
+	if _, err := client.Recommend(context.Background(), 100, "USD", "TR"); err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if after := atomic.LoadInt32(&requests); after != before {
+		t.Errorf("Expected Recommend to be served from a warm cache, got %d upstream requests", after-before)
+	}
+}
+
+func TestWarmerRunReportsFailures(t *testing.T) {
+	var requests int32
+	server := newWarmerTestServer(t, &requests)
+	client := NewClient(WithWorldBankURL(server.URL), WithCurrencyURL(server.URL))
+
+	failures := make(chan *RefreshError, 1)
+	warmer := NewWarmer(client,
+		WithWarmCountries("TR"),
+		WithWarmCurrencies("XYZ"),
+		WithRefreshErrorHandler(func(err *RefreshError) {
+			select {
+			case failures <- err:
+			default:
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- warmer.Run(ctx) }()
+
+	select {
+	case err := <-failures:
+		if err.Family != CacheFamilyRate || err.Key != "XYZ" {
+			t.Errorf("Unexpected refresh error %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("Expected a refresh failure to be reported")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+}
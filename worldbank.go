@@ -20,6 +20,7 @@ const (
 type WorldBankClient struct {
 	baseURL string
 	client  *resty.Client
+	clock   Clock
 }
 
 // NewWorldBankClient creates a new World Bank API client
@@ -30,6 +31,7 @@ func NewWorldBankClient(baseURL string) *WorldBankClient {
 	
 	return &WorldBankClient{
 		baseURL: baseURL,
+		clock:   SystemClock,
 		client: resty.New().
 			SetTimeout(30 * time.Second).
 			SetRetryCount(3).
@@ -39,8 +41,12 @@ func NewWorldBankClient(baseURL string) *WorldBankClient {
 
 // GetPPP fetches the most recent PPP data for a country
 func (w *WorldBankClient) GetPPP(ctx context.Context, countryCode string) (*PPPData, error) {
+	return w.GetLatestPPP(ctx, countryCode, w.clock.Now().Year())
+}
+
+// GetLatestPPP fetches the most recent PPP data for a country up to endYear
+func (w *WorldBankClient) GetLatestPPP(ctx context.Context, countryCode string, endYear int) (*PPPData, error) {
 	// Get data for the last 10 years to find the most recent available
-	endYear := time.Now().Year()
 	startYear := endYear - 10
 	
 	url := fmt.Sprintf("%s/country/%s/indicator/%s", w.baseURL, countryCode, PPPIndicatorCode)
@@ -87,17 +93,21 @@ func (w *WorldBankClient) GetPPP(ctx context.Context, countryCode string) (*PPPD
 		if dp.Value != nil && *dp.Value > 0 {
 			year, _ := strconv.Atoi(dp.Date)
 			return &PPPData{
-				CountryCode: dp.Country.ID,
+				CountryCode: dp.CountryAlpha2(),
 				CountryName: dp.Country.Value,
 				Year:        year,
 				Factor:      *dp.Value,
-				LastUpdated: time.Now(),
+				LastUpdated: w.clock.Now(),
 				Source:      "World Bank",
 			}, nil
 		}
 	}
 	
-	return nil, fmt.Errorf("no PPP data available for country %s", countryCode)
+	return nil, NewPPPError(
+		ErrCodeNoData,
+		fmt.Sprintf("no PPP data available for country %s", countryCode),
+		ErrNoData,
+	).WithContext("country", countryCode)
 }
 
 // GetHistoricalPPP fetches historical PPP data for a country
@@ -145,11 +155,11 @@ func (w *WorldBankClient) GetHistoricalPPP(ctx context.Context, countryCode stri
 		if dp.Value != nil && *dp.Value > 0 {
 			year, _ := strconv.Atoi(dp.Date)
 			results = append(results, PPPData{
-				CountryCode: dp.Country.ID,
+				CountryCode: dp.CountryAlpha2(),
 				CountryName: dp.Country.Value,
 				Year:        year,
 				Factor:      *dp.Value,
-				LastUpdated: time.Now(),
+				LastUpdated: w.clock.Now(),
 				Source:      "World Bank",
 			})
 		}